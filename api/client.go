@@ -83,6 +83,16 @@ func (c *Client) NewGetSpacesRequest() (*http.Request, error) {
 	return req, nil
 }
 
+func (c *Client) NewGetStacksRequest() (*http.Request, error) {
+	req := &http.Request{
+		Method: "GET",
+		URL:    c.BaseUrl,
+	}
+	req.URL.Path = "/v2/stacks"
+
+	return req, nil
+}
+
 func (c *Client) HandleFiltersAndParameters(next func() (*http.Request, error)) func(filter Filter, params map[string]interface{}) (*http.Request, error) {
 	return func(filter Filter, params map[string]interface{}) (*http.Request, error) {
 		req, err := next()
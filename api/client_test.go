@@ -128,6 +128,17 @@ var _ = Describe("Api", func() {
 		})
 	})
 
+	Describe("NewGetStacksRequest", func() {
+		JustBeforeEach(func() {
+			request, err = apiClient.NewGetStacksRequest()
+		})
+
+		It("hits the appropriate API URL", func() {
+			Expect(request.Method).To(Equal("GET"))
+			Expect(request.URL.String()).To(Equal("https://api.my-crazy-domain.com/v2/stacks"))
+		})
+	})
+
 	Describe("EqualFilter", func() {
 		It("serializes to name:val", func() {
 			filter := EqualFilter{
@@ -0,0 +1,309 @@
+package cloudcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+//go:generate counterfeiter . CliConnection
+type CliConnection interface {
+	CliCommandWithoutTerminalOutput(args ...string) ([]string, error)
+}
+
+type Client struct {
+	cli CliConnection
+}
+
+type Error struct {
+	Code        int64  `json:"code,omitempty"`
+	Description string `json:"description,omitempty"`
+	ErrorCode   string `json:"error_code,omitempty"`
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s - %s", e.ErrorCode, e.Description)
+}
+
+type v3Errors struct {
+	Errors []struct {
+		Code   int64  `json:"code"`
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+const (
+	NotStagedErrorCode    = "CF-NotStaged"
+	StagingErrorErrorCode = "CF-StagingError"
+	AppNotFoundErrorCode  = "CF-AppNotFound"
+)
+
+func IsErrorCode(err error, errorCode string) bool {
+	ccErr, ok := err.(Error)
+	return ok && ccErr.ErrorCode == errorCode
+}
+
+// AppParams are the writable attributes of an app. Unset attributes are left
+// unchanged by the Cloud Controller.
+type AppParams struct {
+	Name                    string                    `json:"name,omitempty"`
+	SpaceGuid               string                    `json:"space_guid,omitempty"`
+	Diego                   *bool                     `json:"diego,omitempty"`
+	Memory                  int64                     `json:"memory,omitempty"`
+	Instances               int                       `json:"instances,omitempty"`
+	DiskQuota               int64                     `json:"disk_quota,omitempty"`
+	StackGuid               string                    `json:"stack_guid,omitempty"`
+	Buildpack               string                    `json:"buildpack,omitempty"`
+	Command                 string                    `json:"command,omitempty"`
+	EnvironmentJson         map[string]interface{}    `json:"environment_json,omitempty"`
+	HealthCheckType         string                    `json:"health_check_type,omitempty"`
+	HealthCheckTimeout      int                       `json:"health_check_timeout,omitempty"`
+	DockerImage             string                    `json:"docker_image,omitempty"`
+	DockerCredentials       *models.DockerCredentials `json:"docker_credentials,omitempty"`
+	Ports                   []int                     `json:"ports,omitempty"`
+	HealthCheckHTTPEndpoint string                    `json:"health_check_http_endpoint,omitempty"`
+	EnableSSH               *bool                     `json:"enable_ssh,omitempty"`
+	State                   string                    `json:"state,omitempty"`
+}
+
+func NewClient(cli CliConnection) *Client {
+	return &Client{
+		cli: cli,
+	}
+}
+
+func (c *Client) GetApp(appGuid string) (models.Application, error) {
+	var app models.Application
+
+	body, err := c.curl("/v2/apps/" + appGuid)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	err = json.Unmarshal(body, &app)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	return app, nil
+}
+
+func (c *Client) GetAppInstances(appGuid string) (models.AppInstances, error) {
+	body, err := c.curl("/v2/apps/" + appGuid + "/instances")
+	if err != nil {
+		return models.AppInstances{}, err
+	}
+
+	return models.AppInstancesParser{}.Parse(body)
+}
+
+func (c *Client) GetAppStats(appGuid string) (models.AppStats, error) {
+	body, err := c.curl("/v2/apps/" + appGuid + "/stats")
+	if err != nil {
+		return models.AppStats{}, err
+	}
+
+	return models.AppStatsParser{}.Parse(body)
+}
+
+func (c *Client) CreateApp(params AppParams) (models.Application, error) {
+	return c.writeApp("/v2/apps", "POST", params)
+}
+
+func (c *Client) UpdateApp(appGuid string, params AppParams) (models.Application, error) {
+	return c.writeApp("/v2/apps/"+appGuid, "PUT", params)
+}
+
+func (c *Client) writeApp(path string, method string, params AppParams) (models.Application, error) {
+	var app models.Application
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	body, err := c.curl(path, "-X", method, "-d", string(data))
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	err = json.Unmarshal(body, &app)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	return app, nil
+}
+
+// DeleteApp deletes the app along with its service bindings and route
+// mappings.
+func (c *Client) DeleteApp(appGuid string) error {
+	_, err := c.curl("/v2/apps/"+appGuid+"?recursive=true", "-X", "DELETE")
+	return err
+}
+
+func (c *Client) GetAppRoutes(appGuid string) (models.Routes, error) {
+	var routes models.Routes
+
+	bodies, err := c.curlPages("/v2/apps/" + appGuid + "/routes")
+	if err != nil {
+		return models.Routes{}, err
+	}
+
+	for _, body := range bodies {
+		page, err := models.RoutesParser{}.Parse(body)
+		if err != nil {
+			return models.Routes{}, err
+		}
+		routes = append(routes, page...)
+	}
+
+	return routes, nil
+}
+
+func (c *Client) MapRoute(routeGuid string, appGuid string) error {
+	_, err := c.curl("/v2/routes/"+routeGuid+"/apps/"+appGuid, "-X", "PUT")
+	return err
+}
+
+func (c *Client) UnmapRoute(routeGuid string, appGuid string) error {
+	_, err := c.curl("/v2/routes/"+routeGuid+"/apps/"+appGuid, "-X", "DELETE")
+	return err
+}
+
+func (c *Client) GetAppServiceBindings(appGuid string) (models.ServiceBindings, error) {
+	var bindings models.ServiceBindings
+
+	bodies, err := c.curlPages("/v2/apps/" + appGuid + "/service_bindings")
+	if err != nil {
+		return models.ServiceBindings{}, err
+	}
+
+	for _, body := range bodies {
+		page, err := models.ServiceBindingsParser{}.Parse(body)
+		if err != nil {
+			return models.ServiceBindings{}, err
+		}
+		bindings = append(bindings, page...)
+	}
+
+	return bindings, nil
+}
+
+func (c *Client) BindService(appGuid string, serviceInstanceGuid string) error {
+	data, err := json.Marshal(models.ServiceBindingEntity{
+		AppGuid:             appGuid,
+		ServiceInstanceGuid: serviceInstanceGuid,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.curl("/v2/service_bindings", "-X", "POST", "-d", string(data))
+	return err
+}
+
+func (c *Client) GetCurrentDroplet(appGuid string) (models.Droplet, error) {
+	return c.readDroplet(c.curl("/v3/apps/" + appGuid + "/droplets/current"))
+}
+
+func (c *Client) GetDroplet(dropletGuid string) (models.Droplet, error) {
+	return c.readDroplet(c.curl("/v3/droplets/" + dropletGuid))
+}
+
+// CopyDroplet starts copying the droplet to the app. The copy is usable once
+// its state is STAGED.
+func (c *Client) CopyDroplet(dropletGuid string, appGuid string) (models.Droplet, error) {
+	data := fmt.Sprintf(`{"relationships":{"app":{"data":{"guid":%q}}}}`, appGuid)
+	return c.readDroplet(c.curl("/v3/droplets?source_guid="+dropletGuid, "-X", "POST", "-d", data))
+}
+
+func (c *Client) SetCurrentDroplet(appGuid string, dropletGuid string) error {
+	data := fmt.Sprintf(`{"data":{"guid":%q}}`, dropletGuid)
+	_, err := c.curl("/v3/apps/"+appGuid+"/relationships/current_droplet", "-X", "PATCH", "-d", data)
+	return err
+}
+
+func (c *Client) readDroplet(body []byte, err error) (models.Droplet, error) {
+	var droplet models.Droplet
+
+	if err != nil {
+		return models.Droplet{}, err
+	}
+
+	err = json.Unmarshal(body, &droplet)
+	if err != nil {
+		return models.Droplet{}, err
+	}
+
+	return droplet, nil
+}
+
+// curlPages follows next_url until every page of a v2 list has been fetched.
+func (c *Client) curlPages(path string) ([][]byte, error) {
+	var bodies [][]byte
+
+	for path != "" {
+		body, err := c.curl(path)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, body)
+
+		var page struct {
+			NextUrl string `json:"next_url"`
+		}
+		err = json.Unmarshal(body, &page)
+		if err != nil {
+			return nil, err
+		}
+		path = page.NextUrl
+	}
+
+	return bodies, nil
+}
+
+func (c *Client) curl(args ...string) ([]byte, error) {
+	output, err := c.cli.CliCommandWithoutTerminalOutput(append([]string{"curl"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte(strings.Join(output, "\n"))
+	if err = checkError(body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func checkError(body []byte) error {
+	if strings.TrimSpace(string(body)) == "" {
+		return nil
+	}
+
+	ccErr := Error{}
+	err := json.Unmarshal(body, &ccErr)
+	if err != nil {
+		return err
+	}
+
+	if ccErr.ErrorCode != "" || ccErr.Code != 0 {
+		return ccErr
+	}
+
+	var errs v3Errors
+	err = json.Unmarshal(body, &errs)
+	if err == nil && len(errs.Errors) > 0 {
+		return Error{
+			Code:        errs.Errors[0].Code,
+			Description: errs.Errors[0].Detail,
+			ErrorCode:   errs.Errors[0].Title,
+		}
+	}
+
+	return nil
+}
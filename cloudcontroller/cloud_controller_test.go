@@ -0,0 +1,234 @@
+package cloudcontroller_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller/cloudcontrollerfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		fakeCliConnection *cloudcontrollerfakes.FakeCliConnection
+		client            *cloudcontroller.Client
+	)
+
+	BeforeEach(func() {
+		fakeCliConnection = new(cloudcontrollerfakes.FakeCliConnection)
+		client = cloudcontroller.NewClient(fakeCliConnection)
+	})
+
+	Describe("GetApp", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+				`{"metadata": {"guid": "some-app-guid"},`,
+				`"entity": {"name": "some-app", "state": "STARTED", "instances": 3, "package_state": "STAGED"}}`,
+			}, nil)
+		})
+
+		It("curls the app endpoint", func() {
+			_, err := client.GetApp("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(1))
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputArgsForCall(0)).To(Equal([]string{"curl", "/v2/apps/some-app-guid"}))
+		})
+
+		It("parses the app", func() {
+			app, err := client.GetApp("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(app.Guid).To(Equal("some-app-guid"))
+			Expect(app.Name).To(Equal("some-app"))
+			Expect(app.Instances).To(Equal(3))
+			Expect(app.PackageState).To(Equal(models.PackageStaged))
+		})
+
+		Context("when curl fails", func() {
+			BeforeEach(func() {
+				fakeCliConnection.CliCommandWithoutTerminalOutputReturns(nil, errors.New("curl failed"))
+			})
+
+			It("returns the error", func() {
+				_, err := client.GetApp("some-app-guid")
+				Expect(err).To(MatchError("curl failed"))
+			})
+		})
+
+		Context("when the cloud controller returns an error", func() {
+			BeforeEach(func() {
+				fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+					`{"code": 100004, "description": "The app could not be found: some-app-guid", "error_code": "CF-AppNotFound"}`,
+				}, nil)
+			})
+
+			It("returns the parsed error", func() {
+				_, err := client.GetApp("some-app-guid")
+				Expect(err).To(Equal(cloudcontroller.Error{
+					Code:        100004,
+					Description: "The app could not be found: some-app-guid",
+					ErrorCode:   "CF-AppNotFound",
+				}))
+				Expect(err.Error()).To(Equal("CF-AppNotFound - The app could not be found: some-app-guid"))
+			})
+		})
+	})
+
+	Describe("GetAppInstances", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+				`{"0": {"state": "RUNNING"}, "1": {"state": "CRASHED"}}`,
+			}, nil)
+		})
+
+		It("curls the app instances endpoint", func() {
+			_, err := client.GetAppInstances("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputArgsForCall(0)).To(Equal([]string{"curl", "/v2/apps/some-app-guid/instances"}))
+		})
+
+		It("parses the instances", func() {
+			instances, err := client.GetAppInstances("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(instances).To(HaveLen(2))
+			Expect(instances.Running()).To(Equal(1))
+			Expect(instances.Crashed()).To(Equal(1))
+		})
+
+		Context("when the app has not finished staging", func() {
+			BeforeEach(func() {
+				fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+					`{"code": 170002, "description": "App has not finished staging", "error_code": "CF-NotStaged"}`,
+				}, nil)
+			})
+
+			It("returns the parsed error", func() {
+				_, err := client.GetAppInstances("some-app-guid")
+				Expect(err).To(HaveOccurred())
+
+				ccErr, ok := err.(cloudcontroller.Error)
+				Expect(ok).To(BeTrue())
+				Expect(ccErr.ErrorCode).To(Equal(cloudcontroller.NotStagedErrorCode))
+			})
+		})
+	})
+
+	Describe("GetAppStats", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+				`{"0": {"state": "RUNNING", "stats": {"usage": {"disk": 10}, "disk_quota": 100}}}`,
+			}, nil)
+		})
+
+		It("curls the app stats endpoint", func() {
+			stats, err := client.GetAppStats("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats["0"].Stats.DiskQuota).To(Equal(int64(100)))
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputArgsForCall(0)).To(Equal([]string{"curl", "/v2/apps/some-app-guid/stats"}))
+		})
+	})
+
+	Describe("CreateApp", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+				`{"metadata": {"guid": "some-copy-guid"}, "entity": {"name": "some-app-diego"}}`,
+			}, nil)
+		})
+
+		It("posts only the given attributes", func() {
+			diego := true
+			app, err := client.CreateApp(cloudcontroller.AppParams{
+				Name:      "some-app-diego",
+				SpaceGuid: "some-space-guid",
+				Diego:     &diego,
+				State:     models.Stopped,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(app.Guid).To(Equal("some-copy-guid"))
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputArgsForCall(0)).To(Equal([]string{
+				"curl", "/v2/apps", "-X", "POST", "-d",
+				`{"name":"some-app-diego","space_guid":"some-space-guid","diego":true,"state":"STOPPED"}`,
+			}))
+		})
+	})
+
+	Describe("DeleteApp", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{""}, nil)
+		})
+
+		It("deletes the app and its bindings", func() {
+			err := client.DeleteApp("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputArgsForCall(0)).To(Equal([]string{
+				"curl", "/v2/apps/some-app-guid?recursive=true", "-X", "DELETE",
+			}))
+		})
+	})
+
+	Describe("GetAppRoutes", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputStub = func(args ...string) ([]string, error) {
+				if args[1] == "/v2/apps/some-app-guid/routes" {
+					return []string{`{"next_url": "/v2/apps/some-app-guid/routes?page=2", "resources": [{"metadata": {"guid": "route-1"}}]}`}, nil
+				}
+				return []string{`{"next_url": null, "resources": [{"metadata": {"guid": "route-2"}}]}`}, nil
+			}
+		})
+
+		It("fetches every page", func() {
+			routes, err := client.GetAppRoutes("some-app-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputCallCount()).To(Equal(2))
+			Expect(routes).To(HaveLen(2))
+			Expect(routes[0].Guid).To(Equal("route-1"))
+			Expect(routes[1].Guid).To(Equal("route-2"))
+		})
+	})
+
+	Describe("CopyDroplet", func() {
+		BeforeEach(func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+				`{"guid": "some-new-droplet-guid", "state": "COPYING"}`,
+			}, nil)
+		})
+
+		It("copies the droplet to the app", func() {
+			droplet, err := client.CopyDroplet("some-droplet-guid", "some-copy-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(droplet).To(Equal(models.Droplet{Guid: "some-new-droplet-guid", State: models.DropletCopying}))
+
+			Expect(fakeCliConnection.CliCommandWithoutTerminalOutputArgsForCall(0)).To(Equal([]string{
+				"curl", "/v3/droplets?source_guid=some-droplet-guid", "-X", "POST", "-d",
+				`{"relationships":{"app":{"data":{"guid":"some-copy-guid"}}}}`,
+			}))
+		})
+
+		Context("when the v3 API returns an error", func() {
+			BeforeEach(func() {
+				fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{
+					`{"errors": [{"code": 10010, "title": "CF-ResourceNotFound", "detail": "Droplet not found"}]}`,
+				}, nil)
+			})
+
+			It("returns the parsed error", func() {
+				_, err := client.CopyDroplet("some-droplet-guid", "some-copy-guid")
+				Expect(err).To(Equal(cloudcontroller.Error{
+					Code:        10010,
+					Description: "Droplet not found",
+					ErrorCode:   "CF-ResourceNotFound",
+				}))
+			})
+		})
+	})
+})
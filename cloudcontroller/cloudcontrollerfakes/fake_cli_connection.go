@@ -0,0 +1,55 @@
+// This file was generated by counterfeiter
+package cloudcontrollerfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+)
+
+type FakeCliConnection struct {
+	CliCommandWithoutTerminalOutputStub        func(args ...string) ([]string, error)
+	cliCommandWithoutTerminalOutputMutex       sync.RWMutex
+	cliCommandWithoutTerminalOutputArgsForCall []struct {
+		args []string
+	}
+	cliCommandWithoutTerminalOutputReturns struct {
+		result1 []string
+		result2 error
+	}
+}
+
+func (fake *FakeCliConnection) CliCommandWithoutTerminalOutput(args ...string) ([]string, error) {
+	fake.cliCommandWithoutTerminalOutputMutex.Lock()
+	fake.cliCommandWithoutTerminalOutputArgsForCall = append(fake.cliCommandWithoutTerminalOutputArgsForCall, struct {
+		args []string
+	}{args})
+	fake.cliCommandWithoutTerminalOutputMutex.Unlock()
+	if fake.CliCommandWithoutTerminalOutputStub != nil {
+		return fake.CliCommandWithoutTerminalOutputStub(args...)
+	} else {
+		return fake.cliCommandWithoutTerminalOutputReturns.result1, fake.cliCommandWithoutTerminalOutputReturns.result2
+	}
+}
+
+func (fake *FakeCliConnection) CliCommandWithoutTerminalOutputCallCount() int {
+	fake.cliCommandWithoutTerminalOutputMutex.RLock()
+	defer fake.cliCommandWithoutTerminalOutputMutex.RUnlock()
+	return len(fake.cliCommandWithoutTerminalOutputArgsForCall)
+}
+
+func (fake *FakeCliConnection) CliCommandWithoutTerminalOutputArgsForCall(i int) []string {
+	fake.cliCommandWithoutTerminalOutputMutex.RLock()
+	defer fake.cliCommandWithoutTerminalOutputMutex.RUnlock()
+	return fake.cliCommandWithoutTerminalOutputArgsForCall[i].args
+}
+
+func (fake *FakeCliConnection) CliCommandWithoutTerminalOutputReturns(result1 []string, result2 error) {
+	fake.CliCommandWithoutTerminalOutputStub = nil
+	fake.cliCommandWithoutTerminalOutputReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+var _ cloudcontroller.CliConnection = new(FakeCliConnection)
@@ -3,13 +3,23 @@ package commands
 import (
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/diegohelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/filterhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/listhelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/ui"
 )
 
 type DeaAppsCommand struct {
-	Organization string `short:"o" value-name:"ORG" description:"Organization to restrict the app migration to"`
-	Space        string `short:"s" value-name:"SPACE" description:"Space in the targeted organization to limit results to"`
+	Organization string                    `short:"o" value-name:"ORG" description:"Organization to restrict the app migration to"`
+	Space        string                    `short:"s" value-name:"SPACE" description:"Space in the targeted organization to limit results to"`
+	Include      []flaghelpers.PatternFlag `long:"include" value-name:"PATTERN" description:"Only list apps whose name matches PATTERN, a glob or a regular expression wrapped in slashes; may be repeated"`
+	Exclude      []flaghelpers.PatternFlag `long:"exclude" value-name:"PATTERN" description:"Do not list apps whose name matches PATTERN; may be repeated"`
+	State        string                    `long:"state" value-name:"STATE" choice:"STARTED" choice:"STOPPED" description:"Only list apps in STATE"`
+	Buildpack    flaghelpers.PatternFlag   `long:"buildpack" value-name:"BUILDPACK" description:"Only list apps whose buildpack, or detected buildpack, matches BUILDPACK"`
+	Stack        string                    `long:"stack" value-name:"STACK" description:"Only list apps on STACK"`
+	ExcludeFile  string                    `long:"exclude-file" value-name:"FILE" description:"Do not list the apps whose guids are listed in FILE, one per line"`
+	Columns      string                    `long:"columns" value-name:"COLUMNS" description:"Comma-separated columns of the table: name, space, org, state, instances, memory, disk, stack, buildpack, health-check or package-updated"`
+	Output       string                    `long:"output" value-name:"FORMAT" choice:"table" choice:"json" choice:"yaml" choice:"csv" default:"table" description:"Format of the list: table, json, yaml or csv"`
 }
 
 func (command DeaAppsCommand) Execute([]string) error {
@@ -21,7 +31,29 @@ func (command DeaAppsCommand) Execute([]string) error {
 		return err
 	}
 
-	appsGetter, err := diegohelpers.NewAppsGetterFunc(cliConnection, command.Organization, command.Space, runtime)
+	format := listhelpers.OutputFormat(command.Output)
+	if command.Columns != "" && format.Structured() {
+		return errorhelpers.ColumnsWithStructuredOutputError
+	}
+
+	columns, err := ui.ParseColumns(command.Columns)
+	if err != nil {
+		return err
+	}
+
+	appFilter, err := filterhelpers.NewAppFilter(cliConnection, filterhelpers.Options{
+		Include:     command.Include,
+		Exclude:     command.Exclude,
+		State:       command.State,
+		Buildpack:   command.Buildpack,
+		Stack:       command.Stack,
+		ExcludeFile: command.ExcludeFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	appsGetter, err := diegohelpers.NewAppsGetterFunc(cliConnection, command.Organization, command.Space, runtime, appFilter)
 	if err != nil {
 		return err
 	}
@@ -31,7 +63,9 @@ func (command DeaAppsCommand) Execute([]string) error {
 		return err
 	}
 
-	err = listhelpers.ListApps(cliConnection, appsGetter, &listAppsCommand)
+	listAppsCommand.Columns = columns
+
+	err = listhelpers.ListApps(cliConnection, appsGetter, &listAppsCommand, format)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,7 @@ package commands_test
 import (
 	. "github.com/cloudfoundry-incubator/diego-enabler/commands"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -31,4 +32,30 @@ var _ = Describe("DiegoApps", func() {
 			Expect(err).To(Equal(errorhelpers.SpecifyOrgOrSpaceError))
 		})
 	})
+
+	Context("when columns are passed with structured output", func() {
+		BeforeEach(func() {
+			command = DiegoAppsCommand{
+				Columns: "name,state",
+				Output:  "json",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.ColumnsWithStructuredOutputError))
+		})
+	})
+
+	Context("when an unknown column is passed", func() {
+		BeforeEach(func() {
+			command = DiegoAppsCommand{
+				Columns: "name,colour",
+				Output:  "table",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(ui.InvalidColumnError{Column: "colour"}))
+		})
+	})
 })
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/diegohelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/filterhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/readinesshelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+type DiegoReadinessCommand struct {
+	OptionalArgs DiegoReadinessPositionalArgs `positional-args:"yes"`
+	Organization string                       `short:"o" value-name:"ORG" description:"Organization to restrict the check to"`
+	Space        string                       `short:"s" value-name:"SPACE" description:"Space in the targeted organization to restrict the check to"`
+	DiegoStacks  []string                     `long:"diego-stack" value-name:"STACK" description:"Stack the Diego cells support, instead of the default stacks; may be repeated"`
+}
+
+type DiegoReadinessPositionalArgs struct {
+	AppName string `positional-arg-name:"APP_NAME" description:"The app name"`
+}
+
+func (command DiegoReadinessCommand) Execute([]string) error {
+	cliConnection := DiegoEnabler.CLIConnection
+	appName := command.OptionalArgs.AppName
+
+	err := errorhelpers.ErrorIfOrgAndSpacesSet(command.Organization, command.Space)
+	if err != nil {
+		return err
+	}
+
+	if appName != "" && (command.Organization != "" || command.Space != "") {
+		return errorhelpers.AppNameWithOrgOrSpaceError
+	}
+
+	cmd := readinesshelpers.DiegoReadiness{
+		DiegoStacks: command.DiegoStacks,
+	}
+
+	if appName != "" {
+		app, err := cliConnection.GetApp(appName)
+		if err != nil {
+			return err
+		}
+
+		if app.Guid == "" {
+			return fmt.Errorf("App %s not found", appName)
+		}
+		cmd.AppGuid = app.Guid
+	} else {
+		cmd.AppsGetterFunc, err = diegohelpers.NewAppsGetterFunc(cliConnection, command.Organization, command.Space, ui.DEA, filterhelpers.AppFilter{})
+		if err != nil {
+			return err
+		}
+	}
+
+	readinessCommand, err := readinesshelpers.NewReadinessCommand(cliConnection, command.Organization, command.Space, appName)
+	if err != nil {
+		return err
+	}
+	cmd.ReadinessCommand = &readinessCommand
+
+	return cmd.Execute(cliConnection)
+}
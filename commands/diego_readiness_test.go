@@ -0,0 +1,49 @@
+package commands_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiegoReadinessCommand", func() {
+	var (
+		command DiegoReadinessCommand
+
+		err error
+	)
+
+	JustBeforeEach(func() {
+		err = command.Execute([]string{})
+	})
+
+	Context("when both organization and space are passed", func() {
+		BeforeEach(func() {
+			command = DiegoReadinessCommand{
+				Space:        "some-space",
+				Organization: "some-organization",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.SpecifyOrgOrSpaceError))
+		})
+	})
+
+	Context("when both an app name and an organization are passed", func() {
+		BeforeEach(func() {
+			command = DiegoReadinessCommand{
+				OptionalArgs: DiegoReadinessPositionalArgs{
+					AppName: "some-app",
+				},
+				Organization: "some-organization",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.AppNameWithOrgOrSpaceError))
+		})
+	})
+})
@@ -5,7 +5,11 @@ import (
 	"strings"
 
 	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/filterhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
 	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
 	"github.com/cloudfoundry-incubator/diego-enabler/ui"
 )
@@ -19,6 +23,17 @@ func ToggleDiegoSupport(on bool, cliConnection api.Connection, appName string) e
 		return err
 	}
 
+	// Staging is only waited for when moving to Diego, against the app as it
+	// was before the move.
+	client := cloudcontroller.NewClient(cliConnection)
+	var before models.Application
+	if on {
+		before, err = client.GetApp(app.Guid)
+		if err != nil {
+			return err
+		}
+	}
+
 	if output, err := d.SetDiegoFlag(app.Guid, on); err != nil {
 		return fmt.Errorf("%s\n%s", err, strings.Join(output, "\n"))
 	}
@@ -36,6 +51,63 @@ func ToggleDiegoSupport(on bool, cliConnection api.Connection, appName string) e
 		return fmt.Errorf("Diego support for %s is NOT set to %t\n\n", appName, on)
 	}
 
+	// Started apps are restarted on Diego, which may restage them.
+	if !on || strings.ToLower(app.State) != "started" {
+		return nil
+	}
+
+	fmt.Printf("Waiting for %s to stage\n", appName)
+	err = migratehelpers.WaitForStaging(
+		before,
+		client,
+		migratehelpers.TimeoutsFromEnv().Staging,
+		migratehelpers.DefaultPollInterval,
+	)
+	if err != nil {
+		return fmt.Errorf("App %s %s", appName, err)
+	}
+	ui.SayOK()
+
+	return nil
+}
+
+// BlueGreenDiegoSupport moves a started app to the runtime without restarting
+// it in place. Stopped apps are toggled as usual.
+func BlueGreenDiegoSupport(on bool, cliConnection api.Connection, appName string) error {
+	appModel, err := cliConnection.GetApp(appName)
+	if err != nil {
+		return err
+	}
+
+	client := cloudcontroller.NewClient(cliConnection)
+	app, err := client.GetApp(appModel.Guid)
+	if err != nil {
+		return err
+	}
+
+	if app.State != models.Started || app.Diego == on {
+		return ToggleDiegoSupport(on, cliConnection, appName)
+	}
+
+	runtime := ui.DEA
+	if on {
+		runtime = ui.Diego
+	}
+
+	fmt.Printf("Migrating %s to %s with a blue-green deployment\n", appName, runtime)
+	blueGreen := migratehelpers.BlueGreen{
+		Client:       client,
+		Timeouts:     migratehelpers.TimeoutsFromEnv(),
+		PollInterval: migratehelpers.DefaultPollInterval,
+		Retry:        migratehelpers.DefaultRetry,
+	}
+
+	err = blueGreen.Migrate(app, runtime)
+	if err != nil {
+		return err
+	}
+	ui.SayOK()
+
 	return nil
 }
 
@@ -75,8 +147,11 @@ func NewAppsGetterFunc(
 	orgName string,
 	spaceName string,
 	runtime ui.Runtime,
+	appFilter filterhelpers.AppFilter,
 ) (thingdoer.AppsGetterFunc, error) {
-	diegoAppsCommand := thingdoer.AppsGetter{}
+	diegoAppsCommand := thingdoer.AppsGetter{
+		Filters: appFilter.Filters(),
+	}
 
 	if orgName != "" {
 		org, err := cliConnection.GetOrg(orgName)
@@ -97,5 +172,5 @@ func NewAppsGetterFunc(
 		appsGetterFunc = diegoAppsCommand.DeaApps
 	}
 
-	return appsGetterFunc, nil
+	return appFilter.Apply(appsGetterFunc), nil
 }
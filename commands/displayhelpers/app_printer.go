@@ -1,10 +1,16 @@
 package displayhelpers
 
-import "github.com/cloudfoundry-incubator/diego-enabler/models"
+import (
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
 
 type AppPrinter struct {
 	App    models.Application
 	Spaces map[string]models.Space
+	Stacks map[string]models.Stack
 }
 
 func (a *AppPrinter) Name() string {
@@ -49,3 +55,51 @@ func (a *AppPrinter) Space() string {
 
 	return display
 }
+
+func (a *AppPrinter) State() string {
+	return strings.ToLower(a.App.State)
+}
+
+func (a *AppPrinter) DesiredInstances() int {
+	return a.App.Instances
+}
+
+func (a *AppPrinter) RunningInstances() int {
+	return a.App.RunningInstances
+}
+
+func (a *AppPrinter) Memory() int64 {
+	return a.App.Memory
+}
+
+func (a *AppPrinter) DiskQuota() int64 {
+	return a.App.DiskQuota
+}
+
+func (a *AppPrinter) Stack() string {
+	stack, ok := a.Stacks[a.App.StackGuid]
+	if !ok {
+		return a.App.StackGuid
+	}
+
+	return stack.Name
+}
+
+func (a *AppPrinter) Buildpack() string {
+	switch {
+	case a.App.DockerImage != "":
+		return a.App.DockerImage
+	case a.App.Buildpack != "":
+		return a.App.Buildpack
+	default:
+		return a.App.DetectedBuildpack
+	}
+}
+
+func (a *AppPrinter) HealthCheckType() string {
+	return a.App.HealthCheckType
+}
+
+func (a *AppPrinter) PackageUpdatedAt() *time.Time {
+	return a.App.PackageUpdatedAt
+}
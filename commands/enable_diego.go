@@ -4,6 +4,7 @@ import "github.com/cloudfoundry-incubator/diego-enabler/commands/diegohelpers"
 
 type EnableDiegoCommand struct {
 	RequiredOptions EnableDiegoPositionalArgs `positional-args:"yes"`
+	BlueGreen       bool                      `long:"blue-green" description:"Start a copy of the app on Diego and move the app's routes to it instead of restarting the app in place"`
 }
 
 type EnableDiegoPositionalArgs struct {
@@ -11,5 +12,9 @@ type EnableDiegoPositionalArgs struct {
 }
 
 func (command EnableDiegoCommand) Execute([]string) error {
+	if command.BlueGreen {
+		return diegohelpers.BlueGreenDiegoSupport(true, DiegoEnabler.CLIConnection, command.RequiredOptions.AppName)
+	}
+
 	return diegohelpers.ToggleDiegoSupport(true, DiegoEnabler.CLIConnection, command.RequiredOptions.AppName)
 }
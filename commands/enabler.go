@@ -11,6 +11,9 @@ type Enabler struct {
 	DiegoApps       DiegoAppsCommand       `command:"diego-apps" description:"Lists all apps running on the Diego runtime that are visible to the user"`
 	DeaApps         DeaAppsCommand         `command:"dea-apps" description:"Lists all apps running on the DEA runtime that are visible to the user"`
 	MigrateApps     MigrateAppsCommand     `command:"migrate-apps" description:"Migrate all apps to Diego/DEA"`
+	DiegoReadiness  DiegoReadinessCommand  `command:"diego-readiness" description:"Report known incompatibilities of apps with the Diego runtime"`
+	RuntimeSummary  RuntimeSummaryCommand  `command:"runtime-summary" description:"Summarize how many apps and how much memory run on DEA and on Diego"`
+	RuntimeSnapshot RuntimeSnapshotCommand `command:"runtime-snapshot" description:"Save the runtime of every visible app, compare snapshots and restore them"`
 	UninstallPlugin UninstallHook          `command:"CLI-MESSAGE-UNINSTALL"`
 }
 
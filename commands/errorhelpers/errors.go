@@ -4,6 +4,28 @@ import "errors"
 
 var SpecifyOrgOrSpaceError = errors.New("Cannot specify org together with space.")
 
+var JournalAndResumeError = errors.New("Cannot specify journal together with resume.")
+
+var PlanWithOrgOrSpaceError = errors.New("Cannot specify plan together with org or space.")
+
+var PlanWithFiltersError = errors.New("Cannot specify plan together with include, exclude, state, buildpack, stack or exclude-file.")
+
+var ColumnsWithStructuredOutputError = errors.New("Cannot specify columns together with json, yaml or csv output.")
+
+var AppNameWithOrgOrSpaceError = errors.New("Cannot specify app name together with org or space.")
+
+var SavePlanWithoutDryRunError = errors.New("Cannot save a plan without dry-run.")
+
+var AutoContinueWithoutCanaryError = errors.New("Cannot auto-continue without canary.")
+
+var DescWithoutOrderByError = errors.New("Cannot specify desc without order-by.")
+
+var InvalidConcurrencyLimitError = errors.New("Maximum apps per space or org must not be negative.")
+
+var InvalidTimeoutMultiplierError = errors.New("Timeout multiplier must be greater than 0.")
+
+var TimeoutAndMultiplierError = errors.New("Cannot specify timeout together with timeout multiplier.")
+
 func ErrorIfOrgAndSpacesSet(orgName, spaceName string) error {
 	if orgName != "" && spaceName != "" {
 		return SpecifyOrgOrSpaceError
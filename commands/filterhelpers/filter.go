@@ -0,0 +1,230 @@
+package filterhelpers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+)
+
+// Options are the filters given on the command line. Stack is the name of a
+// stack and ExcludeFile the path of a file listing app guids, one per line.
+type Options struct {
+	Include     []flaghelpers.PatternFlag
+	Exclude     []flaghelpers.PatternFlag
+	State       string
+	Buildpack   flaghelpers.PatternFlag
+	Stack       string
+	ExcludeFile string
+}
+
+func (o Options) Empty() bool {
+	return len(o.Include) == 0 &&
+		len(o.Exclude) == 0 &&
+		o.State == "" &&
+		o.Buildpack.Pattern == "" &&
+		o.Stack == "" &&
+		o.ExcludeFile == ""
+}
+
+// AppFilter narrows the apps a command works on. An app must match one of
+// the include patterns, if any, and none of the exclude patterns.
+type AppFilter struct {
+	Include      []flaghelpers.PatternFlag
+	Exclude      []flaghelpers.PatternFlag
+	State        string
+	Buildpack    flaghelpers.PatternFlag
+	StackGuid    string
+	ExcludeGuids map[string]bool
+}
+
+type StackNotFoundErr struct {
+	StackName string
+}
+
+func (e StackNotFoundErr) Error() string {
+	return fmt.Sprintf("Stack not found: %s", e.StackName)
+}
+
+func NewAppFilter(cliConnection api.Connection, options Options) (AppFilter, error) {
+	appFilter := AppFilter{
+		Include:   options.Include,
+		Exclude:   options.Exclude,
+		State:     options.State,
+		Buildpack: options.Buildpack,
+	}
+
+	if options.Stack != "" {
+		stackGuid, err := findStack(cliConnection, options.Stack)
+		if err != nil {
+			return AppFilter{}, err
+		}
+		appFilter.StackGuid = stackGuid
+	}
+
+	if options.ExcludeFile != "" {
+		guids, err := LoadGuids(options.ExcludeFile)
+		if err != nil {
+			return AppFilter{}, err
+		}
+		appFilter.ExcludeGuids = guids
+	}
+
+	return appFilter, nil
+}
+
+func findStack(cliConnection api.Connection, name string) (string, error) {
+	apiClient, err := api.NewClient(cliConnection)
+	if err != nil {
+		return "", err
+	}
+
+	stackRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetStacksRequest),
+	)
+
+	stackPaginatedRequester, err := api.NewPaginatedRequester(cliConnection, stackRequestFactory)
+	if err != nil {
+		return "", err
+	}
+
+	stacks, err := thingdoer.Stacks(
+		models.StacksParser{},
+		stackPaginatedRequester,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	for _, stack := range stacks {
+		if stack.Name == name {
+			return stack.Guid, nil
+		}
+	}
+
+	return "", StackNotFoundErr{StackName: name}
+}
+
+// LoadGuids reads a file listing app guids, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadGuids(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	guids := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		guids[line] = true
+	}
+
+	return guids, scanner.Err()
+}
+
+// Filters are the parts of the filter the Cloud Controller can query for.
+// The v2 API can only query apps by exact name, so included names go to the
+// Cloud Controller only when none of them is a glob or regular expression.
+func (f AppFilter) Filters() api.Filters {
+	var filters api.Filters
+
+	if names, ok := f.literalIncludes(); ok {
+		filters = append(filters, api.InclusionFilter{
+			Name:   "name",
+			Values: names,
+		})
+	}
+
+	if f.StackGuid != "" {
+		filters = append(filters, api.EqualFilter{
+			Name:  "stack_guid",
+			Value: f.StackGuid,
+		})
+	}
+
+	return filters
+}
+
+func (f AppFilter) literalIncludes() ([]interface{}, bool) {
+	if len(f.Include) == 0 {
+		return nil, false
+	}
+
+	var names []interface{}
+	for _, include := range f.Include {
+		// Values of an IN query are separated by commas.
+		if !include.Literal() || strings.Contains(include.Pattern, ",") {
+			return nil, false
+		}
+		names = append(names, include.Pattern)
+	}
+
+	return names, true
+}
+
+// Matches checks the app against the whole filter, including the parts that
+// were already sent to the Cloud Controller.
+func (f AppFilter) Matches(app models.Application) bool {
+	if len(f.Include) > 0 && !matchesAny(f.Include, app.Name) {
+		return false
+	}
+
+	if matchesAny(f.Exclude, app.Name) || f.ExcludeGuids[app.Guid] {
+		return false
+	}
+
+	if f.State != "" && app.State != f.State {
+		return false
+	}
+
+	if f.Buildpack.Pattern != "" &&
+		!f.Buildpack.Matches(app.Buildpack) &&
+		!f.Buildpack.Matches(app.DetectedBuildpack) {
+		return false
+	}
+
+	if f.StackGuid != "" && app.StackGuid != f.StackGuid {
+		return false
+	}
+
+	return true
+}
+
+func matchesAny(patterns []flaghelpers.PatternFlag, name string) bool {
+	for _, pattern := range patterns {
+		if pattern.Matches(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply drops the apps the filter does not match from those appsGetterFunc
+// returns.
+func (f AppFilter) Apply(appsGetterFunc thingdoer.AppsGetterFunc) thingdoer.AppsGetterFunc {
+	return func(appsParser thingdoer.ApplicationsParser, paginatedRequester thingdoer.PaginatedRequester) (models.Applications, error) {
+		apps, err := appsGetterFunc(appsParser, paginatedRequester)
+		if err != nil {
+			return nil, err
+		}
+
+		var filtered models.Applications
+		for _, app := range apps {
+			if f.Matches(app) {
+				filtered = append(filtered, app)
+			}
+		}
+
+		return filtered, nil
+	}
+}
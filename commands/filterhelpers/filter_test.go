@@ -0,0 +1,158 @@
+package filterhelpers_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/filterhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppFilter", func() {
+	var appFilter AppFilter
+
+	pattern := func(value string) flaghelpers.PatternFlag {
+		var flag flaghelpers.PatternFlag
+		Expect(flag.UnmarshalFlag(value)).To(Succeed())
+		return flag
+	}
+
+	app := func(name string) models.Application {
+		return models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				Name:      name,
+				State:     models.Started,
+				Buildpack: "ruby_buildpack",
+				StackGuid: "cflinuxfs2-guid",
+			},
+			ApplicationMetadata: models.ApplicationMetadata{Guid: name + "-guid"},
+		}
+	}
+
+	BeforeEach(func() {
+		appFilter = AppFilter{}
+	})
+
+	Describe("Filters", func() {
+		It("is empty without filters the Cloud Controller can query for", func() {
+			appFilter.State = models.Started
+			appFilter.Exclude = []flaghelpers.PatternFlag{pattern("api")}
+			Expect(appFilter.Filters()).To(BeEmpty())
+		})
+
+		It("queries for included names and the stack", func() {
+			appFilter.Include = []flaghelpers.PatternFlag{pattern("api"), pattern("worker")}
+			appFilter.StackGuid = "cflinuxfs2-guid"
+
+			Expect(appFilter.Filters()).To(Equal(api.Filters{
+				api.InclusionFilter{Name: "name", Values: []interface{}{"api", "worker"}},
+				api.EqualFilter{Name: "stack_guid", Value: "cflinuxfs2-guid"},
+			}))
+		})
+
+		It("does not query for names when any include is a pattern", func() {
+			appFilter.Include = []flaghelpers.PatternFlag{pattern("api"), pattern("worker-*")}
+			Expect(appFilter.Filters()).To(BeEmpty())
+		})
+	})
+
+	Describe("Matches", func() {
+		It("matches every app without filters", func() {
+			Expect(appFilter.Matches(app("api"))).To(BeTrue())
+		})
+
+		It("matches apps matching any include and no exclude", func() {
+			appFilter.Include = []flaghelpers.PatternFlag{pattern("api-*"), pattern("/^worker/")}
+			appFilter.Exclude = []flaghelpers.PatternFlag{pattern("*-canary")}
+
+			Expect(appFilter.Matches(app("api-v2"))).To(BeTrue())
+			Expect(appFilter.Matches(app("worker-1"))).To(BeTrue())
+			Expect(appFilter.Matches(app("api-canary"))).To(BeFalse())
+			Expect(appFilter.Matches(app("web"))).To(BeFalse())
+		})
+
+		It("does not match excluded guids", func() {
+			appFilter.ExcludeGuids = map[string]bool{"api-guid": true}
+
+			Expect(appFilter.Matches(app("api"))).To(BeFalse())
+			Expect(appFilter.Matches(app("web"))).To(BeTrue())
+		})
+
+		It("matches the state, buildpack and stack", func() {
+			appFilter.State = models.Started
+			appFilter.Buildpack = pattern("ruby*")
+			appFilter.StackGuid = "cflinuxfs2-guid"
+			Expect(appFilter.Matches(app("api"))).To(BeTrue())
+
+			stopped := app("api")
+			stopped.State = models.Stopped
+			Expect(appFilter.Matches(stopped)).To(BeFalse())
+
+			detected := app("api")
+			detected.Buildpack = ""
+			detected.DetectedBuildpack = "ruby 1.6.14"
+			Expect(appFilter.Matches(detected)).To(BeTrue())
+
+			goApp := app("api")
+			goApp.Buildpack = "go_buildpack"
+			Expect(appFilter.Matches(goApp)).To(BeFalse())
+
+			otherStack := app("api")
+			otherStack.StackGuid = "windows2012R2-guid"
+			Expect(appFilter.Matches(otherStack)).To(BeFalse())
+		})
+	})
+
+	Describe("Apply", func() {
+		It("drops the apps the filter does not match", func() {
+			appFilter.Exclude = []flaghelpers.PatternFlag{pattern("web")}
+			appsGetter := appFilter.Apply(func(thingdoer.ApplicationsParser, thingdoer.PaginatedRequester) (models.Applications, error) {
+				return models.Applications{app("api"), app("web")}, nil
+			})
+
+			apps, err := appsGetter(nil, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apps).To(Equal(models.Applications{app("api")}))
+		})
+
+		It("returns the error of the apps getter", func() {
+			appsGetter := appFilter.Apply(func(thingdoer.ApplicationsParser, thingdoer.PaginatedRequester) (models.Applications, error) {
+				return nil, errors.New("boom")
+			})
+
+			_, err := appsGetter(nil, nil)
+			Expect(err).To(MatchError("boom"))
+		})
+	})
+})
+
+var _ = Describe("LoadGuids", func() {
+	var path string
+
+	BeforeEach(func() {
+		file, err := ioutil.TempFile("", "exclude")
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+
+		_, err = file.WriteString("# apps owned by another team\nguid-1\n\n  guid-2  \n")
+		Expect(err).NotTo(HaveOccurred())
+		path = file.Name()
+	})
+
+	AfterEach(func() {
+		os.Remove(path)
+	})
+
+	It("reads a guid from every line", func() {
+		guids, err := LoadGuids(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(guids).To(Equal(map[string]bool{"guid-1": true, "guid-2": true}))
+	})
+})
@@ -0,0 +1,13 @@
+package filterhelpers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFilterhelpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Filterhelpers Suite")
+}
@@ -0,0 +1,58 @@
+package flaghelpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CanaryFlag is a number of apps, or a percentage of them when it ends in %.
+type CanaryFlag struct {
+	Count   int
+	Percent int
+}
+
+func (flag *CanaryFlag) UnmarshalFlag(value string) error {
+	if strings.HasSuffix(value, "%") {
+		percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || percent <= 0 || percent > 100 {
+			return InvalidCanaryValueError{PassedValue: value}
+		}
+
+		flag.Percent = percent
+		return nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		return InvalidCanaryValueError{PassedValue: value}
+	}
+
+	flag.Count = count
+	return nil
+}
+
+// Size returns how many of total apps are canaries. A percentage is rounded
+// up so that it always selects at least one app.
+func (flag CanaryFlag) Size(total int) int {
+	size := flag.Count
+	if flag.Percent > 0 {
+		size = (total*flag.Percent + 99) / 100
+	}
+
+	if size > total {
+		return total
+	}
+	return size
+}
+
+type InvalidCanaryValueError struct {
+	PassedValue string
+}
+
+func (e InvalidCanaryValueError) Error() string {
+	return fmt.Sprintf(
+		"Invalid canary: %s\nValue for CANARY must be a positive integer or a percentage between 1%% and 100%%",
+		e.PassedValue,
+	)
+}
@@ -0,0 +1,53 @@
+package flaghelpers_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CanaryFlag", func() {
+	var canaryFlag CanaryFlag
+
+	BeforeEach(func() {
+		canaryFlag = CanaryFlag{}
+	})
+
+	Describe("a count", func() {
+		It("selects that many apps", func() {
+			err := canaryFlag.UnmarshalFlag("3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(canaryFlag.Size(10)).To(Equal(3))
+		})
+
+		It("selects no more apps than there are", func() {
+			err := canaryFlag.UnmarshalFlag("30")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(canaryFlag.Size(10)).To(Equal(10))
+		})
+	})
+
+	Describe("a percentage", func() {
+		It("selects that share of the apps, rounded up", func() {
+			err := canaryFlag.UnmarshalFlag("5%")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(canaryFlag.Size(30)).To(Equal(2))
+			Expect(canaryFlag.Size(3)).To(Equal(1))
+			Expect(canaryFlag.Size(0)).To(Equal(0))
+		})
+	})
+
+	Describe("invalid values", func() {
+		It("returns an error", func() {
+			for _, value := range []string{"0", "-1", "abc", "0%", "101%", "x%"} {
+				err := canaryFlag.UnmarshalFlag(value)
+				Expect(err).To(MatchError(InvalidCanaryValueError{PassedValue: value}))
+			}
+		})
+	})
+
+	It("selects no apps when unset", func() {
+		Expect(canaryFlag.Size(10)).To(Equal(0))
+	})
+})
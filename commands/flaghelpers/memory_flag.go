@@ -0,0 +1,50 @@
+package flaghelpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var memoryUnits = map[string]int64{
+	"M": 1,
+	"G": 1024,
+	"T": 1024 * 1024,
+}
+
+// MemoryFlag is an amount of memory in megabytes, given with a unit as in
+// `cf scale -m`, e.g. 512M or 64G.
+type MemoryFlag struct {
+	Megabytes int64
+}
+
+func (flag *MemoryFlag) UnmarshalFlag(value string) error {
+	amount := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "B")
+	if amount == "" {
+		return InvalidMemoryValueError{PassedValue: value}
+	}
+
+	multiplier, ok := memoryUnits[amount[len(amount)-1:]]
+	if !ok {
+		return InvalidMemoryValueError{PassedValue: value}
+	}
+
+	megabytes, err := strconv.ParseInt(amount[:len(amount)-1], 10, 64)
+	if err != nil || megabytes <= 0 {
+		return InvalidMemoryValueError{PassedValue: value}
+	}
+
+	flag.Megabytes = megabytes * multiplier
+	return nil
+}
+
+type InvalidMemoryValueError struct {
+	PassedValue string
+}
+
+func (e InvalidMemoryValueError) Error() string {
+	return fmt.Sprintf(
+		"Invalid memory: %s\nValue for MEMORY must be a positive integer with a unit of M, G or T, e.g. 64G",
+		e.PassedValue,
+	)
+}
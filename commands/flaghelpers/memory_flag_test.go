@@ -0,0 +1,39 @@
+package flaghelpers_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryFlag", func() {
+	var memoryFlag MemoryFlag
+
+	BeforeEach(func() {
+		memoryFlag = MemoryFlag{}
+	})
+
+	It("converts the amount to megabytes", func() {
+		for value, megabytes := range map[string]int64{
+			"512M": 512,
+			"512m": 512,
+			"64G":  64 * 1024,
+			"64GB": 64 * 1024,
+			"2T":   2 * 1024 * 1024,
+		} {
+			err := memoryFlag.UnmarshalFlag(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(memoryFlag.Megabytes).To(Equal(megabytes), value)
+		}
+	})
+
+	Describe("invalid values", func() {
+		It("returns an error", func() {
+			for _, value := range []string{"", "64", "G", "0G", "-1G", "1.5G", "64K"} {
+				err := memoryFlag.UnmarshalFlag(value)
+				Expect(err).To(MatchError(InvalidMemoryValueError{PassedValue: value}))
+			}
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package flaghelpers
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PatternFlag matches names against a glob, e.g. api-*, or against a regular
+// expression when wrapped in slashes, e.g. /^api-v[0-9]+$/.
+type PatternFlag struct {
+	Pattern string
+	regexp  *regexp.Regexp
+}
+
+func (flag *PatternFlag) UnmarshalFlag(value string) error {
+	if len(value) > 1 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		re, err := regexp.Compile(value[1 : len(value)-1])
+		if err != nil {
+			return InvalidPatternError{PassedValue: value}
+		}
+		flag.Pattern = value
+		flag.regexp = re
+		return nil
+	}
+
+	if value == "" {
+		return InvalidPatternError{PassedValue: value}
+	}
+	if _, err := path.Match(value, ""); err != nil {
+		return InvalidPatternError{PassedValue: value}
+	}
+
+	flag.Pattern = value
+	flag.regexp = nil
+	return nil
+}
+
+func (flag PatternFlag) Matches(name string) bool {
+	if flag.regexp != nil {
+		return flag.regexp.MatchString(name)
+	}
+
+	matched, _ := path.Match(flag.Pattern, name)
+	return matched
+}
+
+// Literal reports whether the pattern only matches the name it spells out.
+func (flag PatternFlag) Literal() bool {
+	return flag.regexp == nil && !strings.ContainsAny(flag.Pattern, `*?[\`)
+}
+
+type InvalidPatternError struct {
+	PassedValue string
+}
+
+func (e InvalidPatternError) Error() string {
+	return fmt.Sprintf(
+		"Invalid pattern: %s\nPatterns must be globs, e.g. api-*, or regular expressions wrapped in slashes, e.g. /^api-/",
+		e.PassedValue,
+	)
+}
@@ -0,0 +1,64 @@
+package flaghelpers_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PatternFlag", func() {
+	var patternFlag PatternFlag
+
+	BeforeEach(func() {
+		patternFlag = PatternFlag{}
+	})
+
+	Context("when given a glob", func() {
+		BeforeEach(func() {
+			err := patternFlag.UnmarshalFlag("api-*")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("matches names against the glob", func() {
+			Expect(patternFlag.Matches("api-v2")).To(BeTrue())
+			Expect(patternFlag.Matches("web-api-v2")).To(BeFalse())
+			Expect(patternFlag.Literal()).To(BeFalse())
+		})
+	})
+
+	Context("when given a regular expression", func() {
+		BeforeEach(func() {
+			err := patternFlag.UnmarshalFlag("/^api-v[0-9]+$/")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("matches names against the regular expression", func() {
+			Expect(patternFlag.Matches("api-v2")).To(BeTrue())
+			Expect(patternFlag.Matches("api-next")).To(BeFalse())
+			Expect(patternFlag.Literal()).To(BeFalse())
+		})
+	})
+
+	Context("when given a plain name", func() {
+		BeforeEach(func() {
+			err := patternFlag.UnmarshalFlag("api")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("only matches that name", func() {
+			Expect(patternFlag.Matches("api")).To(BeTrue())
+			Expect(patternFlag.Matches("api-v2")).To(BeFalse())
+			Expect(patternFlag.Literal()).To(BeTrue())
+		})
+	})
+
+	Describe("invalid values", func() {
+		It("returns an error", func() {
+			for _, value := range []string{"", "api-[", "/api-(/"} {
+				err := patternFlag.UnmarshalFlag(value)
+				Expect(err).To(MatchError(InvalidPatternError{PassedValue: value}))
+			}
+		})
+	})
+})
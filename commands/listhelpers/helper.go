@@ -2,8 +2,10 @@ package listhelpers
 
 import (
 	"os"
+	"sync"
 
 	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/models"
 	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
@@ -12,8 +14,13 @@ import (
 	"github.com/cloudfoundry/cli/cf/trace"
 )
 
-func ListApps(cliConnection api.Connection, appsGetterFunc thingdoer.AppsGetterFunc, listAppsCommand *ui.ListAppsCommand) error {
-	listAppsCommand.BeforeAll()
+// ListApps prints the apps as a table, or in a structured format written
+// straight to stdout without any of the terminal UI, so that scripts can
+// parse it.
+func ListApps(cliConnection api.Connection, appsGetterFunc thingdoer.AppsGetterFunc, listAppsCommand *ui.ListAppsCommand, format OutputFormat) error {
+	if !format.Structured() {
+		listAppsCommand.BeforeAll()
+	}
 
 	appsParser := models.ApplicationsParser{}
 	spacesParser := models.SpacesParser{}
@@ -61,11 +68,43 @@ func ListApps(cliConnection api.Connection, appsGetterFunc thingdoer.AppsGetterF
 		spaceMap[space.Guid] = space
 	}
 
+	if format.Structured() {
+		return WriteApps(os.Stdout, apps, spaceMap, format)
+	}
+
+	stackMap := make(map[string]models.Stack)
+	if listAppsCommand.Shows(ui.ColumnStack) {
+		stackRequestFactory := apiClient.HandleFiltersAndParameters(
+			apiClient.Authorize(apiClient.NewGetStacksRequest),
+		)
+		stackPaginatedRequester, err := api.NewPaginatedRequester(cliConnection, stackRequestFactory)
+		if err != nil {
+			return err
+		}
+
+		stacks, err := thingdoer.Stacks(
+			models.StacksParser{},
+			stackPaginatedRequester,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, stack := range stacks {
+			stackMap[stack.Guid] = stack
+		}
+	}
+
+	if listAppsCommand.Shows(ui.ColumnInstances) {
+		CountRunningInstances(apps, cloudcontroller.NewClient(cliConnection))
+	}
+
 	var appPrinters []ui.ApplicationPrinter
 	for _, a := range apps {
 		appPrinters = append(appPrinters, &displayhelpers.AppPrinter{
 			App:    a,
 			Spaces: spaceMap,
+			Stacks: stackMap,
 		})
 	}
 
@@ -74,6 +113,52 @@ func ListApps(cliConnection api.Connection, appsGetterFunc thingdoer.AppsGetterF
 	return nil
 }
 
+// instancesInFlight bounds the instances requests made in parallel when
+// counting running instances.
+const instancesInFlight = 10
+
+type InstancesGetter interface {
+	GetAppInstances(string) (models.AppInstances, error)
+}
+
+// CountRunningInstances fills in the running instances of the started apps,
+// fetching the instances of up to instancesInFlight apps at a time. Apps that
+// have not staged yet run no instances, and apps whose instances cannot be
+// fetched get models.UnknownRunningInstances rather than failing the listing.
+func CountRunningInstances(apps models.Applications, instancesGetter InstancesGetter) {
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < instancesInFlight; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				instances, err := instancesGetter.GetAppInstances(apps[i].Guid)
+				switch {
+				case cloudcontroller.IsErrorCode(err, cloudcontroller.NotStagedErrorCode),
+					cloudcontroller.IsErrorCode(err, cloudcontroller.StagingErrorErrorCode):
+					apps[i].RunningInstances = 0
+				case err != nil:
+					apps[i].RunningInstances = models.UnknownRunningInstances
+				default:
+					apps[i].RunningInstances = instances.Running()
+				}
+			}
+		}()
+	}
+
+	for i, app := range apps {
+		if app.State == models.Started {
+			indexes <- i
+		}
+	}
+	close(indexes)
+
+	wg.Wait()
+}
+
 func NewListAppsCommand(cliConnection api.Connection, orgName string, spaceName string, runtime ui.Runtime) (ui.ListAppsCommand, error) {
 	username, err := cliConnection.Username()
 	if err != nil {
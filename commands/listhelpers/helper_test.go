@@ -0,0 +1,108 @@
+package listhelpers_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/listhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CountRunningInstances", func() {
+	var (
+		instancesGetter *migratehelpersfakes.FakeAppStatusGetter
+		apps            models.Applications
+	)
+
+	BeforeEach(func() {
+		instancesGetter = new(migratehelpersfakes.FakeAppStatusGetter)
+		apps = models.Applications{
+			{
+				ApplicationEntity:   models.ApplicationEntity{State: models.Started, Instances: 3},
+				ApplicationMetadata: models.ApplicationMetadata{Guid: "started-app-guid"},
+			},
+			{
+				ApplicationEntity:   models.ApplicationEntity{State: models.Stopped, Instances: 2},
+				ApplicationMetadata: models.ApplicationMetadata{Guid: "stopped-app-guid"},
+			},
+		}
+	})
+
+	It("counts the running instances of started apps", func() {
+		instancesGetter.GetAppInstancesReturns(models.AppInstances{
+			"0": {State: models.InstanceRunning},
+			"1": {State: models.InstanceRunning},
+			"2": {State: models.InstanceCrashed},
+		}, nil)
+
+		CountRunningInstances(apps, instancesGetter)
+
+		Expect(apps[0].RunningInstances).To(Equal(2))
+		Expect(apps[1].RunningInstances).To(BeZero())
+		Expect(instancesGetter.GetAppInstancesCallCount()).To(Equal(1))
+		Expect(instancesGetter.GetAppInstancesArgsForCall(0)).To(Equal("started-app-guid"))
+	})
+
+	It("counts no instances for apps that have not staged", func() {
+		instancesGetter.GetAppInstancesReturns(nil, cloudcontroller.Error{ErrorCode: cloudcontroller.NotStagedErrorCode})
+
+		CountRunningInstances(apps, instancesGetter)
+		Expect(apps[0].RunningInstances).To(BeZero())
+	})
+
+	It("marks the instances of apps that cannot be counted as unknown", func() {
+		instancesGetter.GetAppInstancesReturns(nil, errors.New("boom"))
+
+		CountRunningInstances(apps, instancesGetter)
+		Expect(apps[0].RunningInstances).To(Equal(models.UnknownRunningInstances))
+		Expect(apps[1].RunningInstances).To(BeZero())
+	})
+
+	It("fetches the instances of several apps at a time, but not all at once", func() {
+		apps = nil
+		for i := 0; i < 50; i++ {
+			apps = append(apps, models.Application{
+				ApplicationEntity:   models.ApplicationEntity{State: models.Started, Instances: 1},
+				ApplicationMetadata: models.ApplicationMetadata{Guid: fmt.Sprintf("app-guid-%d", i)},
+			})
+		}
+
+		var (
+			mutex       sync.Mutex
+			inFlight    int
+			maxInFlight int
+		)
+		instancesGetter.GetAppInstancesStub = func(string) (models.AppInstances, error) {
+			mutex.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mutex.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mutex.Lock()
+			inFlight--
+			mutex.Unlock()
+
+			return models.AppInstances{"0": {State: models.InstanceRunning}}, nil
+		}
+
+		CountRunningInstances(apps, instancesGetter)
+
+		Expect(instancesGetter.GetAppInstancesCallCount()).To(Equal(50))
+		for _, app := range apps {
+			Expect(app.RunningInstances).To(Equal(1))
+		}
+		Expect(maxInFlight).To(BeNumerically(">", 1))
+		Expect(maxInFlight).To(BeNumerically("<=", 10))
+	})
+})
@@ -0,0 +1,13 @@
+package listhelpers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestListhelpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Listhelpers Suite")
+}
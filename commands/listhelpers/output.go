@@ -0,0 +1,114 @@
+package listhelpers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"gopkg.in/yaml.v2"
+)
+
+type OutputFormat string
+
+const (
+	OutputTable OutputFormat = "table"
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+	OutputCSV   OutputFormat = "csv"
+)
+
+// Structured reports whether the format is meant for scripts rather than for
+// the terminal.
+func (f OutputFormat) Structured() bool {
+	return f != OutputTable && f != ""
+}
+
+// AppEntry describes an app in the structured output formats.
+type AppEntry struct {
+	Guid             string `json:"guid" yaml:"guid"`
+	Name             string `json:"name" yaml:"name"`
+	Organization     string `json:"org" yaml:"org"`
+	OrganizationGuid string `json:"org_guid" yaml:"org_guid"`
+	Space            string `json:"space" yaml:"space"`
+	SpaceGuid        string `json:"space_guid" yaml:"space_guid"`
+	State            string `json:"state" yaml:"state"`
+	Runtime          string `json:"runtime" yaml:"runtime"`
+}
+
+func NewAppEntry(app models.Application, spaceMap map[string]models.Space) AppEntry {
+	space := spaceMap[app.SpaceGuid]
+
+	organizationGuid := space.OrganizationGuid
+	if organizationGuid == "" {
+		organizationGuid = space.Organization.Guid
+	}
+
+	runtime := "dea"
+	if app.Diego {
+		runtime = "diego"
+	}
+
+	return AppEntry{
+		Guid:             app.Guid,
+		Name:             app.Name,
+		Organization:     space.Organization.Name,
+		OrganizationGuid: organizationGuid,
+		Space:            space.Name,
+		SpaceGuid:        app.SpaceGuid,
+		State:            app.State,
+		Runtime:          runtime,
+	}
+}
+
+// WriteApps writes the apps in one of the structured formats. JSON and YAML
+// are a list of apps, CSV has a header row followed by a row for each app.
+func WriteApps(w io.Writer, apps models.Applications, spaceMap map[string]models.Space, format OutputFormat) error {
+	entries := []AppEntry{}
+	for _, app := range apps {
+		entries = append(entries, NewAppEntry(app, spaceMap))
+	}
+
+	switch format {
+	case OutputJSON:
+		body, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", body)
+		return err
+	case OutputYAML:
+		body, err := yaml.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(body)
+		return err
+	case OutputCSV:
+		return writeCSV(w, entries)
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+}
+
+func writeCSV(w io.Writer, entries []AppEntry) error {
+	csvWriter := csv.NewWriter(w)
+
+	csvWriter.Write([]string{"guid", "name", "org", "org_guid", "space", "space_guid", "state", "runtime"})
+	for _, entry := range entries {
+		csvWriter.Write([]string{
+			entry.Guid,
+			entry.Name,
+			entry.Organization,
+			entry.OrganizationGuid,
+			entry.Space,
+			entry.SpaceGuid,
+			entry.State,
+			entry.Runtime,
+		})
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
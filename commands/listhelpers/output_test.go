@@ -0,0 +1,100 @@
+package listhelpers_test
+
+import (
+	"bytes"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/listhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("WriteApps", func() {
+	var (
+		apps     models.Applications
+		spaceMap map[string]models.Space
+		buf      *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		apps = models.Applications{
+			{
+				ApplicationEntity: models.ApplicationEntity{
+					Name:      "some-app",
+					State:     models.Started,
+					Diego:     true,
+					SpaceGuid: "some-space-guid",
+				},
+				ApplicationMetadata: models.ApplicationMetadata{Guid: "some-app-guid"},
+			},
+		}
+		spaceMap = map[string]models.Space{
+			"some-space-guid": {
+				SpaceEntity: models.SpaceEntity{
+					Name:             "some-space",
+					OrganizationGuid: "some-org-guid",
+					Organization: models.Organization{
+						OrganizationEntity: models.OrganizationEntity{Name: "some-org"},
+					},
+				},
+			},
+		}
+		buf = new(bytes.Buffer)
+	})
+
+	It("writes json", func() {
+		err := WriteApps(buf, apps, spaceMap, OutputJSON)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(MatchJSON(`[{
+			"guid": "some-app-guid",
+			"name": "some-app",
+			"org": "some-org",
+			"org_guid": "some-org-guid",
+			"space": "some-space",
+			"space_guid": "some-space-guid",
+			"state": "STARTED",
+			"runtime": "diego"
+		}]`))
+	})
+
+	It("writes an empty json list without apps", func() {
+		err := WriteApps(buf, nil, spaceMap, OutputJSON)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(MatchJSON(`[]`))
+	})
+
+	It("writes yaml", func() {
+		err := WriteApps(buf, apps, spaceMap, OutputYAML)
+		Expect(err).NotTo(HaveOccurred())
+
+		var entries []AppEntry
+		Expect(yaml.Unmarshal(buf.Bytes(), &entries)).To(Succeed())
+		Expect(entries).To(Equal([]AppEntry{{
+			Guid:             "some-app-guid",
+			Name:             "some-app",
+			Organization:     "some-org",
+			OrganizationGuid: "some-org-guid",
+			Space:            "some-space",
+			SpaceGuid:        "some-space-guid",
+			State:            "STARTED",
+			Runtime:          "diego",
+		}}))
+		Expect(buf.String()).To(ContainSubstring("org_guid: some-org-guid"))
+	})
+
+	It("writes csv with a header row", func() {
+		err := WriteApps(buf, apps, spaceMap, OutputCSV)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf.String()).To(Equal(
+			"guid,name,org,org_guid,space,space_guid,state,runtime\n" +
+				"some-app-guid,some-app,some-org,some-org-guid,some-space,some-space-guid,STARTED,diego\n",
+		))
+	})
+
+	It("rejects unknown formats", func() {
+		err := WriteApps(buf, apps, spaceMap, OutputFormat("xml"))
+		Expect(err).To(MatchError("unknown output format xml"))
+	})
+})
@@ -1,8 +1,11 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/diegohelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/filterhelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/ui"
@@ -16,7 +19,33 @@ type MigrateAppsCommand struct {
 	RequiredOptions MigrateAppsPositionalArgs `positional-args:"yes"`
 	Organization    string                    `short:"o" value-name:"ORG" description:"Organization to restrict the app migration to"`
 	Space           string                    `short:"s" value-name:"SPACE" description:"Space in the targeted organization to restrict the app migration to"`
+	Include         []flaghelpers.PatternFlag `long:"include" value-name:"PATTERN" description:"Only migrate apps whose name matches PATTERN, a glob or a regular expression wrapped in slashes; may be repeated"`
+	Exclude         []flaghelpers.PatternFlag `long:"exclude" value-name:"PATTERN" description:"Do not migrate apps whose name matches PATTERN; may be repeated"`
+	State           string                    `long:"state" value-name:"STATE" choice:"STARTED" choice:"STOPPED" description:"Only migrate apps in STATE"`
+	Buildpack       flaghelpers.PatternFlag   `long:"buildpack" value-name:"BUILDPACK" description:"Only migrate apps whose buildpack, or detected buildpack, matches BUILDPACK"`
+	Stack           string                    `long:"stack" value-name:"STACK" description:"Only migrate apps on STACK"`
+	ExcludeFile     string                    `long:"exclude-file" value-name:"FILE" description:"Do not migrate the apps whose guids are listed in FILE, one per line"`
 	MaxInFlight     flaghelpers.ParallelFlag  `short:"p" value-name:"MAX_IN_FLIGHT" default:"1" description:"Maximum number of apps to migrate in parallel (maximum: 100)"`
+	MaxMemory       flaghelpers.MemoryFlag    `long:"max-memory-in-flight" value-name:"MEMORY" description:"Maximum memory, summed over the instances of the apps being migrated in parallel, e.g. 64G"`
+	MaxPerSpace     int                       `long:"max-per-space" value-name:"MAX_PER_SPACE" description:"Maximum number of apps of a single space to migrate in parallel"`
+	MaxPerOrg       int                       `long:"max-per-org" value-name:"MAX_PER_ORG" description:"Maximum number of apps of a single org to migrate in parallel"`
+	OrderBy         string                    `long:"order-by" value-name:"ORDER" choice:"name" choice:"memory" choice:"instances" choice:"space" choice:"package-updated" description:"Migrate apps in order of name, memory, instances, space or package-updated"`
+	Desc            bool                      `long:"desc" description:"With --order-by, migrate apps in descending order"`
+	Rollback        bool                      `long:"rollback-on-failure" description:"Move apps that fail to start, or run fewer instances than before, on the target runtime back to their original runtime"`
+	Journal         string                    `long:"journal" value-name:"JOURNAL" description:"Record the progress of the migration in JOURNAL"`
+	Resume          string                    `long:"resume" value-name:"JOURNAL" description:"Resume the migration recorded in JOURNAL, skipping apps it has already verified"`
+	DryRun          bool                      `long:"dry-run" description:"List the apps that would be migrated without migrating them"`
+	Plan            string                    `long:"plan" value-name:"PLAN" description:"Migrate the apps listed in PLAN instead of all apps in the org or space"`
+	SavePlan        string                    `long:"save-plan" value-name:"PLAN" description:"With --dry-run, write the apps that would be migrated to PLAN"`
+	BlueGreen       bool                      `long:"blue-green" description:"Start a copy of each started app on the target runtime and move its routes to it instead of restarting the app in place"`
+	Report          string                    `long:"report" value-name:"FILE" description:"Write the outcome of migrating each app to FILE"`
+	ReportFormat    string                    `long:"report-format" value-name:"FORMAT" choice:"json" choice:"csv" choice:"junit" default:"json" description:"Format of the report: json, csv or junit"`
+	Retries         int                       `long:"retries" value-name:"RETRIES" default:"3" description:"Number of times to retry a request that fails with a transient Cloud Controller error"`
+	RetryWait       time.Duration             `long:"retry-wait" value-name:"WAIT" default:"1s" description:"Time to wait before the first retry; the wait doubles with every retry"`
+	Canary          flaghelpers.CanaryFlag    `long:"canary" value-name:"CANARY" description:"Migrate this many apps, or this percentage of apps, first and pause before migrating the rest"`
+	AutoContinue    time.Duration             `long:"auto-continue-after" value-name:"DURATION" description:"With --canary, continue without prompting once the canary apps have stayed healthy for DURATION"`
+	Timeout         time.Duration             `long:"timeout" value-name:"TIMEOUT" description:"Time to wait for each app's instances to start, instead of a time derived from the app's health check timeout and instance count; CF_STARTUP_TIMEOUT is not used"`
+	Multiplier      float64                   `long:"timeout-multiplier" value-name:"MULTIPLIER" default:"1" description:"Factor to scale the time derived for each app to start by"`
 }
 
 //TODO: Figure out how to output this warning in the help
@@ -36,7 +65,66 @@ func (command MigrateAppsCommand) Execute([]string) error {
 		return err
 	}
 
-	appsGetter, err := diegohelpers.NewAppsGetterFunc(cliConnection, command.Organization, command.Space, runtime.Flip())
+	if command.Journal != "" && command.Resume != "" {
+		return errorhelpers.JournalAndResumeError
+	}
+
+	if command.Plan != "" && (command.Organization != "" || command.Space != "") {
+		return errorhelpers.PlanWithOrgOrSpaceError
+	}
+
+	filterOptions := filterhelpers.Options{
+		Include:     command.Include,
+		Exclude:     command.Exclude,
+		State:       command.State,
+		Buildpack:   command.Buildpack,
+		Stack:       command.Stack,
+		ExcludeFile: command.ExcludeFile,
+	}
+
+	if command.Plan != "" && !filterOptions.Empty() {
+		return errorhelpers.PlanWithFiltersError
+	}
+
+	if command.SavePlan != "" && !command.DryRun {
+		return errorhelpers.SavePlanWithoutDryRunError
+	}
+
+	if command.AutoContinue > 0 && command.Canary.Size(1) == 0 {
+		return errorhelpers.AutoContinueWithoutCanaryError
+	}
+
+	if command.Desc && command.OrderBy == "" {
+		return errorhelpers.DescWithoutOrderByError
+	}
+
+	if command.MaxPerSpace < 0 || command.MaxPerOrg < 0 {
+		return errorhelpers.InvalidConcurrencyLimitError
+	}
+
+	if command.Multiplier <= 0 {
+		return errorhelpers.InvalidTimeoutMultiplierError
+	}
+
+	if command.Timeout > 0 && command.Multiplier != 1 {
+		return errorhelpers.TimeoutAndMultiplierError
+	}
+
+	var plan *migratehelpers.Plan
+	if command.Plan != "" {
+		loadedPlan, err := migratehelpers.LoadPlan(command.Plan)
+		if err != nil {
+			return err
+		}
+		plan = &loadedPlan
+	}
+
+	appFilter, err := filterhelpers.NewAppFilter(cliConnection, filterOptions)
+	if err != nil {
+		return err
+	}
+
+	appsGetter, err := diegohelpers.NewAppsGetterFunc(cliConnection, command.Organization, command.Space, runtime.Flip(), appFilter)
 	if err != nil {
 		return err
 	}
@@ -46,11 +134,55 @@ func (command MigrateAppsCommand) Execute([]string) error {
 		return err
 	}
 
+	var journal *migratehelpers.Journal
+	switch {
+	case command.Resume != "":
+		journal, err = migratehelpers.LoadJournal(command.Resume, runtime)
+		if err != nil {
+			return err
+		}
+	case command.Journal != "":
+		journal = migratehelpers.NewJournal(command.Journal, runtime)
+	}
+
+	// CF_STARTUP_TIMEOUT is a single wait for every app, so only --timeout
+	// replaces the time derived for each app.
+	timeouts := migratehelpers.Timeouts{
+		Staging: migratehelpers.TimeoutsFromEnv().Staging,
+		Startup: command.Timeout,
+	}
+
 	cmd := migratehelpers.MigrateApps{
-		MaxInFlight:        command.MaxInFlight.Value,
+		MaxInFlight:       command.MaxInFlight.Value,
+		MaxMemoryInFlight: command.MaxMemory.Megabytes,
+		Ordering: migratehelpers.Ordering{
+			By:   migratehelpers.OrderBy(command.OrderBy),
+			Desc: command.Desc,
+		},
+		Limits: migratehelpers.Limits{
+			PerSpace: command.MaxPerSpace,
+			PerOrg:   command.MaxPerOrg,
+		},
 		Runtime:            runtime,
 		AppsGetterFunc:     appsGetter,
 		MigrateAppsCommand: &migrateAppsCommand,
+		Timeouts:           timeouts,
+		TimeoutMultiplier:  command.Multiplier,
+		PollInterval:       migratehelpers.DefaultPollInterval,
+		RollbackOnFailure:  command.Rollback,
+		Journal:            journal,
+		DryRun:             command.DryRun,
+		Plan:               plan,
+		SavePlan:           command.SavePlan,
+		BlueGreen:          command.BlueGreen,
+		Report:             command.Report,
+		ReportFormat:       migratehelpers.ReportFormat(command.ReportFormat),
+		Retry: migratehelpers.Retry{
+			Retries: command.Retries,
+			Wait:    command.RetryWait,
+		},
+		Canary:            command.Canary,
+		AutoContinueAfter: command.AutoContinue,
 	}
 
 	return cmd.Execute(cliConnection)
@@ -1,6 +1,8 @@
 package commands_test
 
 import (
+	"time"
+
 	. "github.com/cloudfoundry-incubator/diego-enabler/commands"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/ui"
@@ -38,4 +40,121 @@ var _ = Describe("MigrateAppsCommand", func() {
 			Expect(err).To(Equal(errorhelpers.SpecifyOrgOrSpaceError))
 		})
 	})
+
+	Context("when both journal and resume are passed", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				Journal:         "some-journal",
+				Resume:          "some-other-journal",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.JournalAndResumeError))
+		})
+	})
+
+	Context("when both plan and organization are passed", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				Organization:    "some-organization",
+				Plan:            "some-plan",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.PlanWithOrgOrSpaceError))
+		})
+	})
+
+	Context("when both plan and a filter are passed", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				State:           "STARTED",
+				Plan:            "some-plan",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.PlanWithFiltersError))
+		})
+	})
+
+	Context("when save-plan is passed without dry-run", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				SavePlan:        "some-plan",
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.SavePlanWithoutDryRunError))
+		})
+	})
+
+	Context("when the timeout multiplier is not positive", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				Multiplier:      0,
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.InvalidTimeoutMultiplierError))
+		})
+	})
+
+	Context("when both timeout and timeout multiplier are passed", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				Timeout:         time.Minute,
+				Multiplier:      2,
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.TimeoutAndMultiplierError))
+		})
+	})
+
+	Context("when a negative limit per space is passed", func() {
+		BeforeEach(func() {
+			requiredOptions = MigrateAppsPositionalArgs{
+				Runtime: string(ui.Diego),
+			}
+			command = MigrateAppsCommand{
+				RequiredOptions: requiredOptions,
+				MaxPerSpace:     -1,
+				Multiplier:      1,
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.InvalidConcurrencyLimitError))
+		})
+	})
 })
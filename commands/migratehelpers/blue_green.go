@@ -0,0 +1,300 @@
+package migratehelpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+//go:generate counterfeiter . AppCloner
+type AppCloner interface {
+	AppStatusGetter
+	CreateApp(cloudcontroller.AppParams) (models.Application, error)
+	UpdateApp(string, cloudcontroller.AppParams) (models.Application, error)
+	DeleteApp(string) error
+	GetAppRoutes(string) (models.Routes, error)
+	MapRoute(string, string) error
+	UnmapRoute(string, string) error
+	GetAppServiceBindings(string) (models.ServiceBindings, error)
+	BindService(string, string) error
+	GetCurrentDroplet(string) (models.Droplet, error)
+	CopyDroplet(string, string) (models.Droplet, error)
+	GetDroplet(string) (models.Droplet, error)
+	SetCurrentDroplet(string, string) error
+}
+
+// BlueGreen migrates a started app without restarting it in place. A copy of
+// the app is started on the target runtime and takes over the app's routes
+// before the original is deleted.
+type BlueGreen struct {
+	Client       AppCloner
+	Timeouts     Timeouts
+	PollInterval time.Duration
+	// Retry retries polling the copy while waiting for it to start.
+	Retry Retry
+}
+
+type BlueGreenError struct {
+	Step       string
+	Err        error
+	RestoreErr error
+	// Restorable is false once the original app has been deleted.
+	Restorable bool
+	// Changed is false when the step failed before the copy was created.
+	Changed bool
+}
+
+func (e BlueGreenError) Error() string {
+	message := fmt.Sprintf("Failed to %s: %s", e.Step, e.Err)
+
+	switch {
+	case !e.Changed:
+		return message + "; the original app was not changed"
+	case !e.Restorable:
+		return message + "; the original app has already been deleted"
+	case e.RestoreErr != nil:
+		return fmt.Sprintf("%s; could not restore the original app: %s", message, e.RestoreErr)
+	default:
+		return message + "; the original app was restored"
+	}
+}
+
+// Restored reports whether the original app is serving its routes again.
+func (e BlueGreenError) Restored() bool {
+	return e.Restorable && e.RestoreErr == nil
+}
+
+type blueGreenMigration struct {
+	*BlueGreen
+	app      models.Application
+	copyGuid string
+	unmapped models.Routes
+}
+
+func CopyName(appName string, runtime ui.Runtime) string {
+	return appName + "-" + strings.ToLower(runtime.String())
+}
+
+// Migrate moves the app to the runtime. When any step before the original app
+// is deleted fails, the copy is deleted and the original's routes restored.
+func (b *BlueGreen) Migrate(app models.Application, runtime ui.Runtime) error {
+	m := &blueGreenMigration{
+		BlueGreen: b,
+		app:       app,
+	}
+
+	routes, err := b.Client.GetAppRoutes(app.Guid)
+	if err != nil {
+		return m.abort("fetch routes", err)
+	}
+
+	bindings, err := b.Client.GetAppServiceBindings(app.Guid)
+	if err != nil {
+		return m.abort("fetch service bindings", err)
+	}
+
+	diego := runtime == ui.Diego
+	enableSSH := app.EnableSSH
+	params := cloudcontroller.AppParams{
+		Name:                    CopyName(app.Name, runtime),
+		SpaceGuid:               app.SpaceGuid,
+		Diego:                   &diego,
+		Memory:                  app.Memory,
+		Instances:               app.Instances,
+		DiskQuota:               app.DiskQuota,
+		StackGuid:               app.StackGuid,
+		Buildpack:               app.Buildpack,
+		Command:                 app.Command,
+		EnvironmentJson:         app.EnvironmentJson,
+		HealthCheckType:         app.HealthCheckType,
+		HealthCheckTimeout:      app.HealthCheckTimeout,
+		HealthCheckHTTPEndpoint: app.HealthCheckHTTPEndpoint,
+		EnableSSH:               &enableSSH,
+		DockerImage:             app.DockerImage,
+		DockerCredentials:       app.DockerCredentials,
+		State:                   models.Stopped,
+	}
+	// Custom ports are only supported on Diego; the DEAs reject them.
+	if diego {
+		params.Ports = app.Ports
+	}
+
+	clone, err := b.Client.CreateApp(params)
+	if err != nil {
+		return m.abort("create copy", err)
+	}
+	m.copyGuid = clone.Guid
+
+	for _, binding := range bindings {
+		err = b.Client.BindService(clone.Guid, binding.ServiceInstanceGuid)
+		if err != nil {
+			return m.abort("bind services", err)
+		}
+	}
+
+	// Docker apps have no droplet to copy; starting the copy stages it from the
+	// same image.
+	if app.DockerImage == "" {
+		err = m.copyDroplet()
+		if err != nil {
+			return m.abort("copy droplet", err)
+		}
+	}
+
+	_, err = b.Client.UpdateApp(clone.Guid, cloudcontroller.AppParams{State: models.Started})
+	if err != nil {
+		return m.abort("start copy", err)
+	}
+
+	appStatus := retryingAppStatus{
+		AppStatusGetter: b.Client,
+		retry:           b.Retry,
+	}
+	result, err := waitForApp(clone, appStatus, app.Instances, b.Timeouts, b.PollInterval)
+	if result != Success {
+		if err == nil {
+			err = unhealthyError(result)
+		}
+		return m.abort("start copy", err)
+	}
+
+	for _, route := range routes {
+		err = b.Client.MapRoute(route.Guid, clone.Guid)
+		if err != nil {
+			return m.abort("map routes", err)
+		}
+	}
+
+	for _, route := range routes {
+		err = b.Client.UnmapRoute(route.Guid, app.Guid)
+		if err != nil {
+			return m.abort("unmap routes", err)
+		}
+		m.unmapped = append(m.unmapped, route)
+	}
+
+	err = b.Client.DeleteApp(app.Guid)
+	if err != nil {
+		return m.abort("delete original app", err)
+	}
+
+	_, err = b.Client.UpdateApp(clone.Guid, cloudcontroller.AppParams{Name: app.Name})
+	if err != nil {
+		return BlueGreenError{Step: "rename copy", Err: err, Changed: true}
+	}
+
+	return nil
+}
+
+func (m *blueGreenMigration) copyDroplet() error {
+	droplet, err := m.Client.GetCurrentDroplet(m.app.Guid)
+	if err != nil {
+		return err
+	}
+
+	copied, err := m.Client.CopyDroplet(droplet.Guid, m.copyGuid)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.Timeouts.Staging)
+	for copied.State != models.DropletStaged {
+		if copied.State == models.DropletFailed {
+			return fmt.Errorf("droplet %s failed to copy", copied.Guid)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out copying droplet %s", copied.Guid)
+		}
+
+		time.Sleep(m.PollInterval)
+
+		copied, err = m.Client.GetDroplet(copied.Guid)
+		if err != nil {
+			return err
+		}
+	}
+
+	return m.Client.SetCurrentDroplet(m.copyGuid, copied.Guid)
+}
+
+// abort puts the original app's routes back and deletes the copy.
+func (m *blueGreenMigration) abort(step string, err error) error {
+	blueGreenErr := BlueGreenError{
+		Step:       step,
+		Err:        err,
+		Restorable: true,
+		Changed:    m.copyGuid != "",
+	}
+
+	for _, route := range m.unmapped {
+		restoreErr := m.Client.MapRoute(route.Guid, m.app.Guid)
+		if restoreErr != nil && blueGreenErr.RestoreErr == nil {
+			blueGreenErr.RestoreErr = restoreErr
+		}
+	}
+
+	// Deleting the copy also removes its service bindings and route mappings.
+	if m.copyGuid != "" {
+		restoreErr := m.Client.DeleteApp(m.copyGuid)
+		if restoreErr != nil && blueGreenErr.RestoreErr == nil {
+			blueGreenErr.RestoreErr = restoreErr
+		}
+	}
+
+	return blueGreenErr
+}
+
+func unhealthyError(result int) error {
+	switch result {
+	case StagingFailed:
+		return fmt.Errorf("staging failed")
+	case Crashed:
+		return fmt.Errorf("instances crashed")
+	default:
+		return fmt.Errorf("timed out waiting for instances to start")
+	}
+}
+
+// migrateBlueGreen migrates a started app with BlueGreen. An app whose
+// original was restored after a failure counts as rolled back, and one that
+// failed before anything was changed as failed.
+func (cmd *MigrateApps) migrateBlueGreen(
+	appPrinter *displayhelpers.AppPrinter,
+	migrateAppsCommand *ui.MigrateAppsCommand,
+	target Target,
+) (int, error) {
+	blueGreen := BlueGreen{
+		Client:       cmd.AppCloner,
+		Timeouts:     target.Timeouts,
+		PollInterval: cmd.PollInterval,
+		Retry:        cmd.Retry,
+	}
+
+	migrateAppsCommand.PhaseEach(appPrinter, "deploying a copy")
+	printDot := printDots(migrateAppsCommand, appPrinter)
+	err := blueGreen.Migrate(appPrinter.App, target.Runtime)
+	printDot.Stop()
+
+	if err == nil {
+		migrateAppsCommand.CompletedEach(appPrinter)
+		return Success, nil
+	}
+
+	migrateAppsCommand.FailMigrate(appPrinter, err)
+	if blueGreenErr, ok := err.(BlueGreenError); ok {
+		if !blueGreenErr.Changed {
+			return Err, err
+		}
+		if blueGreenErr.Restored() {
+			return RolledBack, err
+		}
+	}
+
+	return RollbackFailed, err
+}
@@ -0,0 +1,311 @@
+package migratehelpers_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BlueGreen", func() {
+	var (
+		client    *migratehelpersfakes.FakeAppCloner
+		blueGreen BlueGreen
+		app       models.Application
+	)
+
+	BeforeEach(func() {
+		client = new(migratehelpersfakes.FakeAppCloner)
+		blueGreen = BlueGreen{
+			Client: client,
+			Timeouts: Timeouts{
+				Staging: 50 * time.Millisecond,
+				Startup: 50 * time.Millisecond,
+			},
+			PollInterval: time.Millisecond,
+			Retry:        Retry{Retries: 1},
+		}
+		app = models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				Name:            "some-app",
+				State:           models.Started,
+				Instances:       2,
+				Memory:          256,
+				SpaceGuid:       "some-space-guid",
+				EnvironmentJson: map[string]interface{}{"SOME_VAR": "some-value"},
+			},
+			ApplicationMetadata: models.ApplicationMetadata{
+				Guid: "some-app-guid",
+			},
+		}
+
+		client.GetAppRoutesReturns(models.Routes{
+			{RouteMetadata: models.RouteMetadata{Guid: "route-1"}},
+			{RouteMetadata: models.RouteMetadata{Guid: "route-2"}},
+		}, nil)
+		client.GetAppServiceBindingsReturns(models.ServiceBindings{
+			{ServiceBindingEntity: models.ServiceBindingEntity{ServiceInstanceGuid: "some-service-guid"}},
+		}, nil)
+		client.CreateAppReturns(models.Application{
+			ApplicationMetadata: models.ApplicationMetadata{Guid: "some-copy-guid"},
+		}, nil)
+		client.GetCurrentDropletReturns(models.Droplet{Guid: "some-droplet-guid", State: models.DropletStaged}, nil)
+		client.CopyDropletReturns(models.Droplet{Guid: "some-copied-droplet-guid", State: models.DropletCopying}, nil)
+		client.GetDropletReturns(models.Droplet{Guid: "some-copied-droplet-guid", State: models.DropletStaged}, nil)
+		client.GetAppReturns(models.Application{
+			ApplicationEntity: models.ApplicationEntity{PackageState: models.PackageStaged},
+		}, nil)
+		client.GetAppInstancesReturns(models.AppInstances{
+			"0": {State: models.InstanceRunning},
+			"1": {State: models.InstanceRunning},
+		}, nil)
+	})
+
+	Context("when every step succeeds", func() {
+		It("creates a stopped copy of the app on the target runtime", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.CreateAppCallCount()).To(Equal(1))
+			params := client.CreateAppArgsForCall(0)
+			Expect(params.Name).To(Equal("some-app-diego"))
+			Expect(*params.Diego).To(BeTrue())
+			Expect(params.SpaceGuid).To(Equal("some-space-guid"))
+			Expect(params.Memory).To(Equal(int64(256)))
+			Expect(params.Instances).To(Equal(2))
+			Expect(params.EnvironmentJson).To(Equal(app.EnvironmentJson))
+			Expect(params.State).To(Equal(models.Stopped))
+		})
+
+		It("copies the app's ports, health check endpoint, SSH setting and docker credentials", func() {
+			app.Ports = []int{8080, 9090}
+			app.HealthCheckType = "http"
+			app.HealthCheckHTTPEndpoint = "/health"
+			app.EnableSSH = true
+			app.DockerCredentials = &models.DockerCredentials{Username: "some-user", Password: "some-password"}
+
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			params := client.CreateAppArgsForCall(0)
+			Expect(params.Ports).To(Equal([]int{8080, 9090}))
+			Expect(params.HealthCheckType).To(Equal("http"))
+			Expect(params.HealthCheckHTTPEndpoint).To(Equal("/health"))
+			Expect(*params.EnableSSH).To(BeTrue())
+			Expect(params.DockerCredentials).To(Equal(app.DockerCredentials))
+		})
+
+		It("does not copy ports to a DEA copy", func() {
+			app.Ports = []int{8080}
+
+			err := blueGreen.Migrate(app, ui.DEA)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.CreateAppArgsForCall(0).Ports).To(BeNil())
+		})
+
+		It("binds the app's services to the copy", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.BindServiceCallCount()).To(Equal(1))
+			appGuid, serviceGuid := client.BindServiceArgsForCall(0)
+			Expect(appGuid).To(Equal("some-copy-guid"))
+			Expect(serviceGuid).To(Equal("some-service-guid"))
+		})
+
+		It("gives the copy the app's droplet", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			dropletGuid, appGuid := client.CopyDropletArgsForCall(0)
+			Expect(dropletGuid).To(Equal("some-droplet-guid"))
+			Expect(appGuid).To(Equal("some-copy-guid"))
+
+			Expect(client.GetDropletCallCount()).To(Equal(1))
+
+			appGuid, dropletGuid = client.SetCurrentDropletArgsForCall(0)
+			Expect(appGuid).To(Equal("some-copy-guid"))
+			Expect(dropletGuid).To(Equal("some-copied-droplet-guid"))
+		})
+
+		It("moves the routes to the copy, deletes the app and renames the copy", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.MapRouteCallCount()).To(Equal(2))
+			routeGuid, appGuid := client.MapRouteArgsForCall(1)
+			Expect(routeGuid).To(Equal("route-2"))
+			Expect(appGuid).To(Equal("some-copy-guid"))
+
+			Expect(client.UnmapRouteCallCount()).To(Equal(2))
+			routeGuid, appGuid = client.UnmapRouteArgsForCall(1)
+			Expect(routeGuid).To(Equal("route-2"))
+			Expect(appGuid).To(Equal("some-app-guid"))
+
+			Expect(client.DeleteAppCallCount()).To(Equal(1))
+			Expect(client.DeleteAppArgsForCall(0)).To(Equal("some-app-guid"))
+
+			Expect(client.UpdateAppCallCount()).To(Equal(2))
+			appGuid, params := client.UpdateAppArgsForCall(0)
+			Expect(appGuid).To(Equal("some-copy-guid"))
+			Expect(params).To(Equal(cloudcontroller.AppParams{State: models.Started}))
+			appGuid, params = client.UpdateAppArgsForCall(1)
+			Expect(appGuid).To(Equal("some-copy-guid"))
+			Expect(params).To(Equal(cloudcontroller.AppParams{Name: "some-app"}))
+		})
+	})
+
+	Context("when the app is a docker app", func() {
+		BeforeEach(func() {
+			app.DockerImage = "some/image"
+		})
+
+		It("does not copy a droplet", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.CreateAppArgsForCall(0).DockerImage).To(Equal("some/image"))
+			Expect(client.GetCurrentDropletCallCount()).To(Equal(0))
+			Expect(client.CopyDropletCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when polling the copy fails transiently", func() {
+		BeforeEach(func() {
+			calls := 0
+			client.GetAppInstancesStub = func(string) (models.AppInstances, error) {
+				calls++
+				if calls == 1 {
+					return nil, cloudcontroller.Error{ErrorCode: "CF-ServiceUnavailable"}
+				}
+				return models.AppInstances{
+					"0": {State: models.InstanceRunning},
+					"1": {State: models.InstanceRunning},
+				}, nil
+			}
+		})
+
+		It("retries and moves the routes to the copy", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.MapRouteCallCount()).To(Equal(2))
+			Expect(client.DeleteAppArgsForCall(0)).To(Equal("some-app-guid"))
+		})
+	})
+
+	Context("when the copy does not start", func() {
+		BeforeEach(func() {
+			client.GetAppInstancesReturns(models.AppInstances{
+				"0": {State: models.InstanceCrashed},
+				"1": {State: models.InstanceCrashed},
+			}, nil)
+		})
+
+		It("deletes the copy and leaves the app's routes alone", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).To(MatchError("Failed to start copy: instances crashed; the original app was restored"))
+			Expect(err.(BlueGreenError).Restored()).To(BeTrue())
+
+			Expect(client.MapRouteCallCount()).To(Equal(0))
+			Expect(client.UnmapRouteCallCount()).To(Equal(0))
+			Expect(client.DeleteAppCallCount()).To(Equal(1))
+			Expect(client.DeleteAppArgsForCall(0)).To(Equal("some-copy-guid"))
+		})
+	})
+
+	Context("when the copied droplet fails", func() {
+		BeforeEach(func() {
+			client.GetDropletReturns(models.Droplet{Guid: "some-copied-droplet-guid", State: models.DropletFailed}, nil)
+		})
+
+		It("deletes the copy without starting it", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(BlueGreenError).Step).To(Equal("copy droplet"))
+
+			Expect(client.UpdateAppCallCount()).To(Equal(0))
+			Expect(client.DeleteAppArgsForCall(0)).To(Equal("some-copy-guid"))
+		})
+	})
+
+	Context("when creating the copy fails", func() {
+		BeforeEach(func() {
+			client.CreateAppReturns(models.Application{}, errors.New("name taken"))
+		})
+
+		It("has nothing to clean up", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).To(MatchError("Failed to create copy: name taken; the original app was not changed"))
+
+			Expect(client.DeleteAppCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when unmapping a route from the app fails", func() {
+		BeforeEach(func() {
+			client.UnmapRouteStub = func(routeGuid string, appGuid string) error {
+				if routeGuid == "route-2" {
+					return errors.New("unmap failed")
+				}
+				return nil
+			}
+		})
+
+		It("maps the routes already unmapped back to the app and deletes the copy", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).To(MatchError("Failed to unmap routes: unmap failed; the original app was restored"))
+
+			Expect(client.MapRouteCallCount()).To(Equal(3))
+			routeGuid, appGuid := client.MapRouteArgsForCall(2)
+			Expect(routeGuid).To(Equal("route-1"))
+			Expect(appGuid).To(Equal("some-app-guid"))
+
+			Expect(client.DeleteAppCallCount()).To(Equal(1))
+			Expect(client.DeleteAppArgsForCall(0)).To(Equal("some-copy-guid"))
+		})
+	})
+
+	Context("when restoring the app fails", func() {
+		BeforeEach(func() {
+			client.GetAppInstancesReturns(models.AppInstances{
+				"0": {State: models.InstanceCrashed},
+			}, nil)
+			client.DeleteAppReturns(errors.New("delete failed"))
+		})
+
+		It("reports that the app was not restored", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).To(MatchError("Failed to start copy: instances crashed; could not restore the original app: delete failed"))
+			Expect(err.(BlueGreenError).Restored()).To(BeFalse())
+		})
+	})
+
+	Context("when renaming the copy fails", func() {
+		BeforeEach(func() {
+			client.UpdateAppStub = func(appGuid string, params cloudcontroller.AppParams) (models.Application, error) {
+				if params.Name != "" {
+					return models.Application{}, errors.New("rename failed")
+				}
+				return models.Application{}, nil
+			}
+		})
+
+		It("does not try to restore the deleted app", func() {
+			err := blueGreen.Migrate(app, ui.Diego)
+			Expect(err).To(MatchError("Failed to rename copy: rename failed; the original app has already been deleted"))
+			Expect(err.(BlueGreenError).Restored()).To(BeFalse())
+
+			Expect(client.DeleteAppCallCount()).To(Equal(1))
+			Expect(client.DeleteAppArgsForCall(0)).To(Equal("some-app-guid"))
+		})
+	})
+})
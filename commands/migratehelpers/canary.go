@@ -0,0 +1,188 @@
+package migratehelpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+)
+
+type CanaryError struct {
+	Reason string
+}
+
+func (e CanaryError) Error() string {
+	return "Migration stopped after the canary apps: " + e.Reason
+}
+
+// CanaryGate checks that migrated apps keep running the instances their
+// migration was verified against for a while before the rest of the apps are
+// migrated. Expected holds those instance counts by app guid; apps missing
+// from it must keep all of their instances running.
+type CanaryGate struct {
+	AppStatus    AppStatusGetter
+	PollInterval time.Duration
+	Duration     time.Duration
+	Expected     map[string]int
+}
+
+// Watch polls the started apps until the gate's duration elapses or stop is
+// closed, and fails as soon as any of them is not running all its instances.
+func (g CanaryGate) Watch(apps models.Applications, stop <-chan struct{}) error {
+	deadline := time.Now().Add(g.Duration)
+
+	for {
+		for _, app := range apps {
+			if app.State != models.Started {
+				continue
+			}
+
+			instances, err := g.AppStatus.GetAppInstances(app.Guid)
+			if err != nil {
+				return CanaryError{Reason: fmt.Sprintf("could not check %s: %s", app.Name, err)}
+			}
+
+			expected, ok := g.Expected[app.Guid]
+			if !ok || expected == 0 {
+				expected = app.Instances
+			}
+
+			if running := instances.Running(); running < expected {
+				return CanaryError{Reason: fmt.Sprintf("%s has %d of %d instances running", app.Name, running, expected)}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(g.PollInterval):
+		}
+	}
+}
+
+// migrateInPhases migrates the canary apps first and only migrates the rest
+// once the canaries succeeded and were either confirmed by the user or stayed
+// healthy for AutoContinueAfter.
+func (cmd *MigrateApps) migrateInPhases(
+	cliConnection api.Connection,
+	apps models.Applications,
+	spaceMap map[string]models.Space,
+	appPaginatedRequester thingdoer.PaginatedRequester,
+	appStatus AppStatusGetter,
+	interrupter *Interrupter,
+) ([]migrationResult, error) {
+	canaries := cmd.Canary.Size(len(apps))
+	if canaries == 0 || canaries == len(apps) {
+		return cmd.migrateApps(cliConnection, apps, spaceMap, cmd.MaxInFlight, interrupter.Stopped()), nil
+	}
+
+	cmd.MigrateAppsCommand.BeforeCanaries(canaries)
+	results := cmd.migrateApps(cliConnection, apps[:canaries], spaceMap, cmd.MaxInFlight, interrupter.Stopped())
+	if interrupter.Interrupted() {
+		return results, nil
+	}
+
+	remaining := len(apps) - canaries
+	cmd.MigrateAppsCommand.AfterCanaries(summarize(results), remaining)
+
+	err := cmd.gateCanaries(results, appPaginatedRequester, appStatus, remaining, interrupter)
+	if err != nil || interrupter.Interrupted() {
+		return results, err
+	}
+
+	return append(results, cmd.migrateApps(cliConnection, apps[canaries:], spaceMap, cmd.MaxInFlight, interrupter.Stopped())...), nil
+}
+
+func (cmd *MigrateApps) gateCanaries(
+	results []migrationResult,
+	appPaginatedRequester thingdoer.PaginatedRequester,
+	appStatus AppStatusGetter,
+	remaining int,
+	interrupter *Interrupter,
+) error {
+	var failed int
+	for _, result := range results {
+		if result.Outcome != Success {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return CanaryError{Reason: fmt.Sprintf("%d of %d canary apps failed to migrate", failed, len(results))}
+	}
+
+	if cmd.AutoContinueAfter <= 0 {
+		confirmed := interrupter.Confirm(func() bool {
+			return cmd.MigrateAppsCommand.ConfirmContinue(remaining)
+		})
+		if !confirmed && !interrupter.Interrupted() {
+			return CanaryError{Reason: "not confirmed"}
+		}
+		return nil
+	}
+
+	canaryApps, err := cmd.canaryApps(results, appPaginatedRequester)
+	if err != nil {
+		return err
+	}
+
+	// canaryApps returns one app per result, in the same order.
+	expected := map[string]int{}
+	for i, app := range canaryApps {
+		expected[app.Guid] = results[i].ExpectedRunning
+	}
+
+	cmd.MigrateAppsCommand.WatchCanaries(cmd.AutoContinueAfter)
+	gate := CanaryGate{
+		AppStatus:    appStatus,
+		PollInterval: cmd.PollInterval,
+		Duration:     cmd.AutoContinueAfter,
+		Expected:     expected,
+	}
+
+	return gate.Watch(canaryApps, interrupter.Stopped())
+}
+
+// canaryApps returns the migrated canary apps. Apps migrated blue-green were
+// replaced by a copy with a new guid, which is looked up by name and space.
+func (cmd *MigrateApps) canaryApps(results []migrationResult, appPaginatedRequester thingdoer.PaginatedRequester) (models.Applications, error) {
+	var apps models.Applications
+
+	for _, result := range results {
+		app := result.AppPrinter.App
+		if !cmd.BlueGreen || app.State != models.Started {
+			apps = append(apps, app)
+			continue
+		}
+
+		replacements, err := thingdoer.Apps(
+			models.ApplicationsParser{},
+			appPaginatedRequester,
+			api.Filters{
+				api.EqualFilter{
+					Name:  "name",
+					Value: app.Name,
+				},
+				api.EqualFilter{
+					Name:  "space_guid",
+					Value: app.SpaceGuid,
+				},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(replacements) != 1 {
+			return nil, CanaryError{Reason: fmt.Sprintf("could not find %s after migrating it", app.Name)}
+		}
+		apps = append(apps, replacements[0])
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,126 @@
+package migratehelpers_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CanaryGate", func() {
+	var (
+		appStatus *migratehelpersfakes.FakeAppStatusGetter
+		gate      CanaryGate
+		apps      models.Applications
+		stop      chan struct{}
+	)
+
+	BeforeEach(func() {
+		appStatus = new(migratehelpersfakes.FakeAppStatusGetter)
+		gate = CanaryGate{
+			AppStatus:    appStatus,
+			PollInterval: time.Millisecond,
+			Duration:     10 * time.Millisecond,
+		}
+		apps = models.Applications{
+			{
+				ApplicationEntity:   models.ApplicationEntity{Name: "started-app", State: models.Started, Instances: 2},
+				ApplicationMetadata: models.ApplicationMetadata{Guid: "started-app-guid"},
+			},
+			{
+				ApplicationEntity:   models.ApplicationEntity{Name: "stopped-app", State: models.Stopped, Instances: 1},
+				ApplicationMetadata: models.ApplicationMetadata{Guid: "stopped-app-guid"},
+			},
+		}
+		stop = make(chan struct{})
+
+		appStatus.GetAppInstancesReturns(models.AppInstances{
+			"0": {State: models.InstanceRunning},
+			"1": {State: models.InstanceRunning},
+		}, nil)
+	})
+
+	Context("when the started apps stay healthy", func() {
+		It("polls them until the duration elapses", func() {
+			err := gate.Watch(apps, stop)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(appStatus.GetAppInstancesCallCount()).To(BeNumerically(">", 1))
+			for i := 0; i < appStatus.GetAppInstancesCallCount(); i++ {
+				Expect(appStatus.GetAppInstancesArgsForCall(i)).To(Equal("started-app-guid"))
+			}
+		})
+	})
+
+	Context("when an instance stops running", func() {
+		BeforeEach(func() {
+			appStatus.GetAppInstancesReturns(models.AppInstances{
+				"0": {State: models.InstanceRunning},
+				"1": {State: models.InstanceCrashed},
+			}, nil)
+		})
+
+		It("fails", func() {
+			err := gate.Watch(apps, stop)
+			Expect(err).To(MatchError("Migration stopped after the canary apps: started-app has 1 of 2 instances running"))
+		})
+	})
+
+	Context("when an app ran fewer instances than desired before its migration", func() {
+		BeforeEach(func() {
+			gate.Expected = map[string]int{"started-app-guid": 1}
+			appStatus.GetAppInstancesReturns(models.AppInstances{
+				"0": {State: models.InstanceRunning},
+				"1": {State: models.InstanceCrashed},
+			}, nil)
+		})
+
+		It("only requires the instances the migration was verified against", func() {
+			err := gate.Watch(apps, stop)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it runs fewer than those", func() {
+			BeforeEach(func() {
+				gate.Expected = map[string]int{"started-app-guid": 2}
+			})
+
+			It("fails", func() {
+				err := gate.Watch(apps, stop)
+				Expect(err).To(MatchError("Migration stopped after the canary apps: started-app has 1 of 2 instances running"))
+			})
+		})
+	})
+
+	Context("when the instances cannot be fetched", func() {
+		BeforeEach(func() {
+			appStatus.GetAppInstancesReturns(nil, errors.New("boom"))
+		})
+
+		It("fails", func() {
+			err := gate.Watch(apps, stop)
+			Expect(err).To(MatchError("Migration stopped after the canary apps: could not check started-app: boom"))
+		})
+	})
+
+	Context("when stopped", func() {
+		BeforeEach(func() {
+			gate.Duration = time.Hour
+			close(stop)
+		})
+
+		It("returns without waiting for the duration", func() {
+			done := make(chan error)
+			go func() {
+				done <- gate.Watch(apps, stop)
+			}()
+
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+})
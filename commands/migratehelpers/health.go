@@ -0,0 +1,281 @@
+package migratehelpers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+const (
+	DefaultPollInterval   = 5 * time.Second
+	DefaultStagingTimeout = 15 * time.Minute
+	DefaultStartupTimeout = 5 * time.Minute
+
+	// DefaultHealthCheckTimeout is the Cloud Controller's default for apps
+	// without a health_check_timeout.
+	DefaultHealthCheckTimeout = 60 * time.Second
+
+	// startupOverhead covers placing an instance and downloading its droplet,
+	// which happen before the health check timeout starts counting.
+	startupOverhead = 30 * time.Second
+
+	// instanceOverhead is added for every instance beyond the first, as cells
+	// start an app's instances only roughly in parallel.
+	instanceOverhead = 5 * time.Second
+)
+
+//go:generate counterfeiter . AppStatusGetter
+type AppStatusGetter interface {
+	GetApp(string) (models.Application, error)
+	GetAppInstances(string) (models.AppInstances, error)
+}
+
+// Timeouts bounds how long a migrated app may take to stage and to get all of
+// its instances running. They mirror CF_STAGING_TIMEOUT and CF_STARTUP_TIMEOUT
+// as used by `cf start`. MigrateApps waits StartupTimeout for apps when Startup
+// is zero.
+type Timeouts struct {
+	Staging time.Duration
+	Startup time.Duration
+}
+
+func TimeoutsFromEnv() Timeouts {
+	return Timeouts{
+		Staging: minutesFromEnv("CF_STAGING_TIMEOUT", DefaultStagingTimeout),
+		Startup: minutesFromEnv("CF_STARTUP_TIMEOUT", DefaultStartupTimeout),
+	}
+}
+
+// StartupTimeout is how long to wait for the app's instances to start: the
+// app's own health check timeout plus some overhead per instance, scaled by
+// multiplier.
+func StartupTimeout(app models.Application, multiplier float64) time.Duration {
+	healthCheckTimeout := DefaultHealthCheckTimeout
+	if app.HealthCheckTimeout > 0 {
+		healthCheckTimeout = time.Duration(app.HealthCheckTimeout) * time.Second
+	}
+
+	timeout := healthCheckTimeout + startupOverhead
+	if app.Instances > 1 {
+		timeout += time.Duration(app.Instances-1) * instanceOverhead
+	}
+
+	if multiplier <= 0 {
+		return timeout
+	}
+	return time.Duration(float64(timeout) * multiplier)
+}
+
+func minutesFromEnv(name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	minutes, err := strconv.ParseFloat(value, 64)
+	if err != nil || minutes <= 0 {
+		return defaultValue
+	}
+
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+// StagingError is the reason the Cloud Controller gives for an app failing to
+// stage, e.g. NoAppDetectedError when no buildpack supports the app.
+type StagingError struct {
+	Reason      string
+	Description string
+}
+
+func (e StagingError) Error() string {
+	switch {
+	case e.Reason != "" && e.Description != "":
+		return fmt.Sprintf("staging failed: %s - %s", e.Reason, e.Description)
+	case e.Reason != "":
+		return "staging failed: " + e.Reason
+	default:
+		return "staging failed"
+	}
+}
+
+func stagingError(app models.Application) StagingError {
+	return StagingError{
+		Reason:      app.StagingFailedReason,
+		Description: app.StagingFailedDescription,
+	}
+}
+
+// stagingSettlePolls is how many polls an app may keep the package it was
+// staged with before a runtime change before it is concluded that the change
+// did not restage it.
+const stagingSettlePolls = 3
+
+// stagingWatch tells when an app has staged after a runtime change. The
+// package the app was staged with before the change does not count until the
+// app was restaged, its package was replaced, or it was polled
+// stagingSettlePolls times without either happening.
+type stagingWatch struct {
+	before      models.Application
+	restaged    bool
+	stagedPolls int
+}
+
+func newStagingWatch(before models.Application) *stagingWatch {
+	return &stagingWatch{
+		before:   before,
+		restaged: before.PackageState != models.PackageStaged,
+	}
+}
+
+// staged reports whether the app, as just polled, has staged since the change.
+func (w *stagingWatch) staged(app models.Application) bool {
+	if app.PackageState != models.PackageStaged {
+		w.restaged = true
+		return false
+	}
+
+	if !samePackageUpdate(app, w.before) {
+		w.restaged = true
+	}
+
+	w.stagedPolls++
+	return w.restaged || w.stagedPolls > stagingSettlePolls
+}
+
+func samePackageUpdate(app models.Application, before models.Application) bool {
+	if app.PackageUpdatedAt == nil || before.PackageUpdatedAt == nil {
+		return app.PackageUpdatedAt == before.PackageUpdatedAt
+	}
+	return app.PackageUpdatedAt.Equal(*before.PackageUpdatedAt)
+}
+
+// WaitForStaging polls the Cloud Controller until the app has staged after a
+// runtime change. before is the app as it was before the change. It returns a
+// StagingError when staging fails.
+func WaitForStaging(before models.Application, appStatus AppStatusGetter, timeout time.Duration, pollInterval time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	watch := newStagingWatch(before)
+
+	for {
+		app, err := appStatus.GetApp(before.Guid)
+		if err != nil {
+			return err
+		}
+
+		if app.PackageState == models.PackageFailed {
+			return stagingError(app)
+		}
+		if watch.staged(app) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for app to stage")
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func (cmd *MigrateApps) waitForApp(appPrinter *displayhelpers.AppPrinter, appStatus AppStatusGetter, expectedRunning int, timeouts Timeouts) (int, error) {
+	var phase string
+	appStatus = progressAppStatus{
+		AppStatusGetter: retryingAppStatus{
+			AppStatusGetter: appStatus,
+			retry:           cmd.Retry,
+		},
+		app:                appPrinter,
+		migrateAppsCommand: cmd.MigrateAppsCommand,
+		expectedRunning:    expectedRunning,
+		phase:              &phase,
+	}
+
+	return waitForApp(appPrinter.App, appStatus, expectedRunning, timeouts, cmd.PollInterval)
+}
+
+// DegradedError reports an app that came up with fewer running instances than
+// expected, e.g. for lack of capacity on the target runtime.
+type DegradedError struct {
+	Running  int
+	Expected int
+}
+
+func (e DegradedError) Error() string {
+	return fmt.Sprintf("%d of %d instances running", e.Running, e.Expected)
+}
+
+// waitForApp polls the Cloud Controller until at least expectedRunning
+// instances of the app are running, staging has failed, or the timeouts elapse.
+// before is the app as it was before its runtime changed; its staged package
+// only counts as described for stagingWatch. A staging failure is returned
+// with the StagingError the Cloud Controller reports, and an app running some
+// but not enough instances once the timeout elapses is Degraded.
+func waitForApp(before models.Application, appStatus AppStatusGetter, expectedRunning int, timeouts Timeouts, pollInterval time.Duration) (int, error) {
+	var (
+		appGuid     = before.Guid
+		watch       = newStagingWatch(before)
+		staged      bool
+		lastRunning int
+		lastCrashed int
+		deadline    = time.Now().Add(timeouts.Staging)
+	)
+
+	for {
+		if !staged {
+			app, err := appStatus.GetApp(appGuid)
+			if err != nil {
+				return Err, err
+			}
+
+			if app.PackageState == models.PackageFailed {
+				return StagingFailed, stagingError(app)
+			}
+			if watch.staged(app) {
+				staged = true
+				deadline = time.Now().Add(timeouts.Startup)
+				continue
+			}
+		} else {
+			instances, err := appStatus.GetAppInstances(appGuid)
+			switch {
+			case cloudcontroller.IsErrorCode(err, cloudcontroller.NotStagedErrorCode):
+				staged = false
+			case cloudcontroller.IsErrorCode(err, cloudcontroller.StagingErrorErrorCode):
+				app, appErr := appStatus.GetApp(appGuid)
+				if appErr != nil {
+					return StagingFailed, StagingError{}
+				}
+				return StagingFailed, stagingError(app)
+			case err != nil:
+				return Err, err
+			default:
+				lastRunning = instances.Running()
+				lastCrashed = instances.Crashed()
+				if lastRunning >= expectedRunning {
+					return Success, nil
+				}
+
+				if len(instances) > 0 && lastCrashed == len(instances) {
+					return Crashed, nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if lastRunning > 0 {
+				return Degraded, DegradedError{Running: lastRunning, Expected: expectedRunning}
+			}
+			if lastCrashed > 0 {
+				return Crashed, nil
+			}
+			return TimedOut, nil
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
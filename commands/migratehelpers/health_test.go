@@ -0,0 +1,166 @@
+package migratehelpers_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WaitForStaging", func() {
+	var (
+		appStatus *migratehelpersfakes.FakeAppStatusGetter
+		before    models.Application
+		app       models.Application
+	)
+
+	BeforeEach(func() {
+		appStatus = new(migratehelpersfakes.FakeAppStatusGetter)
+		updatedAt := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+		before = models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				PackageState:     models.PackageStaged,
+				PackageUpdatedAt: &updatedAt,
+			},
+			ApplicationMetadata: models.ApplicationMetadata{Guid: "some-app-guid"},
+		}
+		app = before
+		app.PackageState = models.PackagePending
+		appStatus.GetAppStub = func(string) (models.Application, error) {
+			current := app
+			app.PackageState = models.PackageStaged
+			return current, nil
+		}
+	})
+
+	It("waits until the app has staged again", func() {
+		err := WaitForStaging(before, appStatus, time.Second, time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(appStatus.GetAppCallCount()).To(Equal(2))
+		Expect(appStatus.GetAppArgsForCall(0)).To(Equal("some-app-guid"))
+	})
+
+	Context("when the app still reports the package it was staged with before", func() {
+		BeforeEach(func() {
+			calls := 0
+			appStatus.GetAppStub = func(string) (models.Application, error) {
+				calls++
+				if calls == 1 {
+					return before, nil
+				}
+				return app, nil
+			}
+			app.PackageState = models.PackageFailed
+			app.StagingFailedReason = "BuildpackCompileFailed"
+		})
+
+		It("does not take it for the staged app", func() {
+			err := WaitForStaging(before, appStatus, time.Second, time.Millisecond)
+			Expect(err).To(MatchError(ContainSubstring("BuildpackCompileFailed")))
+		})
+	})
+
+	Context("when the app was restaged with a new package", func() {
+		BeforeEach(func() {
+			updatedAt := before.PackageUpdatedAt.Add(time.Minute)
+			app.PackageState = models.PackageStaged
+			app.PackageUpdatedAt = &updatedAt
+			appStatus.GetAppStub = nil
+			appStatus.GetAppReturns(app, nil)
+		})
+
+		It("returns at once", func() {
+			err := WaitForStaging(before, appStatus, time.Second, time.Millisecond)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(appStatus.GetAppCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("when the runtime change does not restage the app", func() {
+		BeforeEach(func() {
+			appStatus.GetAppStub = nil
+			appStatus.GetAppReturns(before, nil)
+		})
+
+		It("returns after a few polls", func() {
+			err := WaitForStaging(before, appStatus, time.Second, time.Millisecond)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(appStatus.GetAppCallCount()).To(BeNumerically(">", 1))
+		})
+	})
+
+	Context("when staging fails", func() {
+		BeforeEach(func() {
+			appStatus.GetAppStub = nil
+			appStatus.GetAppReturns(models.Application{
+				ApplicationEntity: models.ApplicationEntity{
+					PackageState:             models.PackageFailed,
+					StagingFailedReason:      "BuildpackCompileFailed",
+					StagingFailedDescription: "App staging failed in the buildpack compile phase",
+				},
+			}, nil)
+		})
+
+		It("returns the staging failure reason", func() {
+			err := WaitForStaging(before, appStatus, time.Second, time.Millisecond)
+			Expect(err).To(Equal(StagingError{
+				Reason:      "BuildpackCompileFailed",
+				Description: "App staging failed in the buildpack compile phase",
+			}))
+			Expect(err).To(MatchError("staging failed: BuildpackCompileFailed - App staging failed in the buildpack compile phase"))
+		})
+	})
+
+	Context("when staging does not finish in time", func() {
+		BeforeEach(func() {
+			appStatus.GetAppStub = nil
+			appStatus.GetAppReturns(app, nil)
+		})
+
+		It("times out", func() {
+			err := WaitForStaging(before, appStatus, 5*time.Millisecond, time.Millisecond)
+			Expect(err).To(MatchError("timed out waiting for app to stage"))
+		})
+	})
+})
+
+var _ = Describe("StartupTimeout", func() {
+	var app models.Application
+
+	BeforeEach(func() {
+		app = models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				Instances:          1,
+				HealthCheckTimeout: 180,
+			},
+		}
+	})
+
+	It("gives the app its health check timeout plus time to place the instance", func() {
+		Expect(StartupTimeout(app, 1)).To(Equal(210 * time.Second))
+	})
+
+	It("gives each additional instance more time", func() {
+		app.Instances = 5
+		Expect(StartupTimeout(app, 1)).To(Equal(230 * time.Second))
+	})
+
+	It("scales the timeout by the multiplier", func() {
+		Expect(StartupTimeout(app, 1.5)).To(Equal(315 * time.Second))
+	})
+
+	Context("when the app has no health check timeout", func() {
+		BeforeEach(func() {
+			app.HealthCheckTimeout = 0
+		})
+
+		It("uses the Cloud Controller's default", func() {
+			Expect(StartupTimeout(app, 1)).To(Equal(DefaultHealthCheckTimeout + 30*time.Second))
+		})
+	})
+})
@@ -1,36 +1,110 @@
 package migratehelpers
 
 import (
+	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"sync"
 
 	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
 	"github.com/cloudfoundry-incubator/diego-enabler/models"
 	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
 	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
 )
 
 const (
 	Success = iota
 	Warning
 	Err
+	StagingFailed
+	Crashed
+	TimedOut
+	RolledBack
+	RollbackFailed
+	Degraded
+	NotAttempted
 )
 
 type MigrateApps struct {
 	MaxInFlight        int
+	MaxMemoryInFlight  int64
+	Limits             Limits
+	RouteGroups        RouteGroups
+	Ordering           Ordering
 	Runtime            ui.Runtime
 	AppsGetterFunc     thingdoer.AppsGetterFunc
 	MigrateAppsCommand *ui.MigrateAppsCommand
+	Timeouts           Timeouts
+	TimeoutMultiplier  float64
+	PollInterval       time.Duration
+	RollbackOnFailure  bool
+	Journal            *Journal
+	DryRun             bool
+	Plan               *Plan
+	SavePlan           string
+	Targets            map[string]Target
+	BlueGreen          bool
+	AppCloner          AppCloner
+	Report             string
+	ReportFormat       ReportFormat
+	Signals            chan os.Signal
+	Retry              Retry
+	Canary             flaghelpers.CanaryFlag
+	AutoContinueAfter  time.Duration
+
+	// held are the apps held back because an app sharing their routes failed.
+	held      models.Applications
+	heldMutex sync.Mutex
+
+	// abandoned is set once in-flight apps are given up on after an
+	// interrupt, from when their journal state is no longer updated.
+	abandoned      bool
+	abandonedMutex sync.Mutex
+}
+
+// HeldError reports apps that were not migrated because an app sharing their
+// routes failed to migrate.
+type HeldError struct {
+	Held int
+}
+
+func (e HeldError) Error() string {
+	return fmt.Sprintf("%d apps sharing a route with an app that failed to migrate were not migrated", e.Held)
+}
+
+// MigrationResult is the outcome of migrating an app. Attempts counts the
+// attempts needed to set the diego flag, including retries. PreviousRuntime
+// is the runtime the app ran on before it was first flipped, which may have
+// happened in a previous run. ExpectedRunning is the number of instances the
+// app had to run on the target runtime.
+type MigrationResult struct {
+	Outcome         int
+	Err             error
+	Attempts        int
+	PreviousRuntime ui.Runtime
+	ExpectedRunning int
+}
+
+type migrationResult struct {
+	MigrationResult
+	AppPrinter *displayhelpers.AppPrinter
+	Duration   time.Duration
 }
 
 func (cmd *MigrateApps) Execute(cliConnection api.Connection) error {
-	cmd.MigrateAppsCommand.BeforeAll() //move me to the command
+	if cmd.DryRun {
+		cmd.MigrateAppsCommand.BeforeDryRun()
+	} else {
+		cmd.MigrateAppsCommand.BeforeAll() //move me to the command
+	}
 
 	apiClient, err := api.NewClient(cliConnection)
 	if err != nil {
@@ -46,14 +120,6 @@ func (cmd *MigrateApps) Execute(cliConnection api.Connection) error {
 		return err
 	}
 
-	apps, err := cmd.AppsGetterFunc(
-		models.ApplicationsParser{},
-		appPaginatedRequester,
-	)
-	if err != nil {
-		return err
-	}
-
 	spaceRequestFactory := apiClient.HandleFiltersAndParameters(
 		apiClient.Authorize(apiClient.NewGetSpacesRequest),
 	)
@@ -76,10 +142,121 @@ func (cmd *MigrateApps) Execute(cliConnection api.Connection) error {
 		spaceMap[space.Guid] = space
 	}
 
-	warnings, errors := cmd.migrateApps(cliConnection, apps, spaceMap, cmd.MaxInFlight)
-	cmd.MigrateAppsCommand.AfterAll(len(apps), warnings, errors)
+	appStatus := cloudcontroller.NewClient(cliConnection)
+	if cmd.BlueGreen && cmd.AppCloner == nil {
+		cmd.AppCloner = appStatus
+	}
+
+	var apps models.Applications
+	if cmd.Plan != nil {
+		apps, cmd.Targets, err = cmd.Plan.Resolve(
+			models.ApplicationsParser{},
+			appPaginatedRequester,
+			appStatus,
+			spaces,
+			Target{Runtime: cmd.Runtime, Timeouts: cmd.Timeouts},
+		)
+	} else {
+		apps, err = cmd.AppsGetterFunc(
+			models.ApplicationsParser{},
+			appPaginatedRequester,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	if cmd.Journal != nil {
+		apps, err = cmd.Journal.Resume(apps, appStatus)
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd.Ordering.Sort(apps, spaceMap)
+
+	if cmd.Journal != nil && !cmd.DryRun {
+		defer cmd.Journal.Close()
+
+		err = cmd.Journal.Add(apps)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cmd.DryRun {
+		var appPrinters []ui.ApplicationPrinter
+		for _, app := range apps {
+			appPrinters = append(appPrinters, &displayhelpers.AppPrinter{
+				App:    app,
+				Spaces: spaceMap,
+			})
+		}
+
+		cmd.MigrateAppsCommand.DryRun(appPrinters)
+
+		if cmd.SavePlan != "" {
+			return cmd.plan(apps, spaceMap).Save(cmd.SavePlan)
+		}
+		return nil
+	}
+
+	// Apps sharing a route are migrated one after another, and held back once
+	// one of them fails, however many apps are migrated in parallel.
+	if cmd.RouteGroups == nil {
+		cmd.RouteGroups = FindRouteGroups(apps, appStatus, cmd.Retry, func(app models.Application, err error) {
+			cmd.MigrateAppsCommand.RoutesWarning(&displayhelpers.AppPrinter{
+				App:    app,
+				Spaces: spaceMap,
+			}, err)
+		})
+	}
 
-	return nil
+	if cmd.MigrateAppsCommand.Progress == nil {
+		cmd.MigrateAppsCommand.Progress = ui.NewProgress(len(apps))
+	}
+
+	signals := cmd.Signals
+	if signals == nil {
+		var stopNotify func()
+		signals, stopNotify = notifyInterrupts()
+		defer stopNotify()
+	}
+
+	interrupter := WatchInterrupts(signals, cmd.MigrateAppsCommand.Interrupted, func() {
+		cmd.MigrateAppsCommand.ForceExit()
+		os.Exit(ForcedExitCode)
+	})
+
+	results, migrateErr := cmd.migrateInPhases(cliConnection, apps, spaceMap, appPaginatedRequester, appStatus, interrupter)
+	interrupter.Close()
+	cmd.MigrateAppsCommand.Progress.Stop()
+
+	summary := summarize(results)
+	summary.Interrupted = interrupter.Interrupted()
+	summary.NotAttempted = len(apps) - len(results)
+	cmd.MigrateAppsCommand.AfterAll(summary)
+
+	if cmd.Report != "" {
+		err = cmd.report(apps, spaceMap, results).Save(cmd.Report, cmd.ReportFormat)
+		if err != nil {
+			return err
+		}
+	}
+
+	if summary.Interrupted {
+		interruptedErr := InterruptedError{NotAttempted: summary.NotAttempted}
+		if cmd.Journal != nil {
+			interruptedErr.Journal = cmd.Journal.Path
+		}
+		return interruptedErr
+	}
+
+	if migrateErr == nil && len(cmd.held) > 0 {
+		return HeldError{Held: len(cmd.held)}
+	}
+
+	return migrateErr
 }
 
 func NewMigrateAppsCommand(cliConnection api.Connection, organizationName string, spaceName string, runtime ui.Runtime) (ui.MigrateAppsCommand, error) {
@@ -96,15 +273,20 @@ func NewMigrateAppsCommand(cliConnection api.Connection, organizationName string
 		organizationName = space.Organization.Name
 	}
 
+	traceEnv := os.Getenv("CF_TRACE")
+	traceLogger := trace.NewLogger(false, traceEnv, "")
+	tUI := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+
 	return ui.MigrateAppsCommand{
 		Username:     username,
 		Runtime:      runtime,
 		Organization: organizationName,
 		Space:        spaceName,
+		UI:           tUI,
 	}, nil
 }
 
-type migrateAppFunc func(appPrinter *displayhelpers.AppPrinter, diegoSupport DiegoFlagSetter) int
+type migrateAppFunc func(appPrinter *displayhelpers.AppPrinter, diegoSupport DiegoFlagSetter, appStatus AppStatusGetter) MigrationResult
 
 //go:generate counterfeiter . DiegoFlagSetter
 type DiegoFlagSetter interface {
@@ -114,86 +296,359 @@ type DiegoFlagSetter interface {
 func (cmd *MigrateApps) MigrateApp(
 	appPrinter *displayhelpers.AppPrinter,
 	diegoSupport DiegoFlagSetter,
-) int {
-	cmd.MigrateAppsCommand.BeforeEach(appPrinter)
-
-	var waitTime time.Duration
-	if appPrinter.App.State == models.Started {
-		waitTime = 1 * time.Minute
-		timeout := os.Getenv("CF_STARTUP_TIMEOUT")
-		if timeout != "" {
-			t, err := strconv.Atoi(timeout)
-
-			if err == nil {
-				waitTime = time.Duration(float32(t)/5.0*60.0) * time.Second
+	appStatus AppStatusGetter,
+) MigrationResult {
+	retryingDiegoSupport := &retryingFlagSetter{
+		DiegoFlagSetter: diegoSupport,
+		retry:           cmd.Retry,
+	}
+	previousRuntime, alreadyFlipped := cmd.previousRuntime(appPrinter, cmd.targetFor(appPrinter))
+
+	runningBefore := appPrinter.App.Instances
+	if appPrinter.App.State == models.Started && !alreadyFlipped {
+		runningBefore = cmd.runningInstances(appPrinter.App, appStatus)
+	}
+
+	result, err := cmd.migrateApp(appPrinter, retryingDiegoSupport, appStatus, runningBefore)
+	cmd.MigrateAppsCommand.AfterEach(appPrinter, result != Success && result != Warning)
+
+	if result == Success {
+		cmd.record(appPrinter, JournalVerified)
+	} else {
+		cmd.record(appPrinter, JournalFailed)
+	}
+
+	return MigrationResult{
+		Outcome:         result,
+		Err:             err,
+		Attempts:        1 + retryingDiegoSupport.retries,
+		PreviousRuntime: previousRuntime,
+		ExpectedRunning: expectedRunning(appPrinter.App, runningBefore),
+	}
+}
+
+// expectedRunning is the number of instances the app must run on the target
+// runtime: as many as it ran before. An app that ran none is expected to run
+// all of them.
+func expectedRunning(app models.Application, runningBefore int) int {
+	if runningBefore == 0 {
+		return app.Instances
+	}
+	return runningBefore
+}
+
+// migrateApp migrates the app, which ran runningBefore instances on its
+// current runtime.
+func (cmd *MigrateApps) migrateApp(
+	appPrinter *displayhelpers.AppPrinter,
+	diegoSupport DiegoFlagSetter,
+	appStatus AppStatusGetter,
+	runningBefore int,
+) (int, error) {
+	target := cmd.targetFor(appPrinter)
+	migrateAppsCommand := cmd.commandFor(target)
+	migrateAppsCommand.BeforeEach(appPrinter)
+
+	// An app flipped by a previous, interrupted run only needs to be verified.
+	originalRuntime, alreadyFlipped := cmd.previousRuntime(appPrinter, target)
+	expected := expectedRunning(appPrinter.App, runningBefore)
+
+	// Stopped apps are not serving traffic, so flipping their flag is enough.
+	if cmd.BlueGreen && appPrinter.App.State == models.Started && !alreadyFlipped {
+		return cmd.migrateBlueGreen(appPrinter, migrateAppsCommand, target)
+	}
+
+	if !alreadyFlipped {
+		migrateAppsCommand.PhaseEach(appPrinter, "switching runtime")
+		_, err := diegoSupport.SetDiegoFlag(appPrinter.App.Guid, target.Runtime == ui.Diego)
+		if err != nil {
+			if strings.Contains(err.Error(), "NotAuthorized") {
+				migrateAppsCommand.UserWarning(appPrinter)
+				return Warning, err
+			} else {
+				migrateAppsCommand.FailMigrate(appPrinter, err)
+				return Err, err
 			}
 		}
 	}
+	cmd.record(appPrinter, JournalFlipped)
 
-	_, err := diegoSupport.SetDiegoFlag(appPrinter.App.Guid, cmd.Runtime == ui.Diego)
-	if err != nil {
-		if strings.Contains(err.Error(), "NotAuthorized") {
-			cmd.MigrateAppsCommand.UserWarning(appPrinter)
-			return Warning
-		} else {
-			cmd.MigrateAppsCommand.FailMigrate(appPrinter, err)
-			return Err
-		}
+	if appPrinter.App.State != models.Started {
+		migrateAppsCommand.CompletedEach(appPrinter)
+		return Success, nil
 	}
 
+	printDot := printDots(migrateAppsCommand, appPrinter)
+	result, err := cmd.waitForApp(appPrinter, appStatus, expected, target.Timeouts)
+	printDot.Stop()
+
+	switch result {
+	case StagingFailed:
+		migrateAppsCommand.FailStaging(appPrinter, err)
+	case Crashed:
+		migrateAppsCommand.FailStart(appPrinter)
+	case TimedOut:
+		migrateAppsCommand.TimedOutEach(appPrinter)
+	case Degraded:
+		degraded := err.(DegradedError)
+		migrateAppsCommand.DegradedEach(appPrinter, degraded.Running, degraded.Expected)
+	case Err:
+		migrateAppsCommand.FailVerify(appPrinter, err)
+	default:
+		migrateAppsCommand.CompletedEach(appPrinter)
+	}
+
+	if err == nil && result != Success {
+		err = unhealthyError(result)
+	}
+
+	// Only an app that changed runtime has a runtime to roll back to.
+	if cmd.RollbackOnFailure && needsRollback(result) && originalRuntime != target.Runtime {
+		return cmd.rollback(appPrinter, diegoSupport, appStatus, target, originalRuntime, runningBefore, err)
+	}
+
+	return result, err
+}
+
+func printDots(migrateAppsCommand *ui.MigrateAppsCommand, appPrinter *displayhelpers.AppPrinter) *time.Ticker {
 	printDot := time.NewTicker(5 * time.Second)
 	go func() {
 		for range printDot.C {
-			cmd.MigrateAppsCommand.DuringEach(appPrinter)
+			migrateAppsCommand.DuringEach(appPrinter)
 		}
 	}()
 
-	time.Sleep(waitTime)
-	printDot.Stop()
+	return printDot
+}
+
+// targetFor returns the runtime and timeouts the app is migrated with, which
+// a plan may override per app. Without a startup timeout, the app's own
+// StartupTimeout is used.
+func (cmd *MigrateApps) targetFor(appPrinter *displayhelpers.AppPrinter) Target {
+	target, ok := cmd.Targets[appPrinter.App.Guid]
+	if !ok {
+		target = Target{
+			Runtime:  cmd.Runtime,
+			Timeouts: cmd.Timeouts,
+		}
+	}
+
+	if target.Timeouts.Startup == 0 {
+		target.Timeouts.Startup = StartupTimeout(appPrinter.App, cmd.TimeoutMultiplier)
+	}
 
-	cmd.MigrateAppsCommand.CompletedEach(appPrinter)
+	return target
+}
+
+func (cmd *MigrateApps) commandFor(target Target) *ui.MigrateAppsCommand {
+	if target.Runtime == cmd.MigrateAppsCommand.Runtime {
+		return cmd.MigrateAppsCommand
+	}
+
+	migrateAppsCommand := *cmd.MigrateAppsCommand
+	migrateAppsCommand.Runtime = target.Runtime
+	return &migrateAppsCommand
+}
+
+func (cmd *MigrateApps) plan(apps models.Applications, spaceMap map[string]models.Space) Plan {
+	var plan Plan
+
+	for _, app := range apps {
+		appPrinter := &displayhelpers.AppPrinter{
+			App:    app,
+			Spaces: spaceMap,
+		}
+		target := cmd.targetFor(appPrinter)
+
+		entry := PlanEntry{
+			Guid:         app.Guid,
+			Organization: appPrinter.Organization(),
+			Space:        appPrinter.Space(),
+			Name:         app.Name,
+			Runtime:      strings.ToLower(target.Runtime.String()),
+		}
+		if planned, ok := cmd.Targets[app.Guid]; ok && planned.Timeouts.Startup != cmd.Timeouts.Startup {
+			entry.Timeout = planned.Timeouts.Startup.String()
+		}
 
-	return Success
+		plan.Apps = append(plan.Apps, entry)
+	}
+
+	return plan
+}
+
+// previousRuntime returns the runtime the app ran on before it was migrated,
+// and whether a previous run already flipped it to the target runtime. An app
+// the previous run failed to verify after flipping it is on the target
+// runtime too.
+func (cmd *MigrateApps) previousRuntime(appPrinter *displayhelpers.AppPrinter, target Target) (ui.Runtime, bool) {
+	flipped := cmd.journaled(appPrinter, JournalFlipped) || cmd.journaled(appPrinter, JournalFailed)
+	if flipped && appPrinter.App.Diego == (target.Runtime == ui.Diego) {
+		return target.Runtime.Flip(), true
+	}
+
+	if appPrinter.App.Diego {
+		return ui.Diego, false
+	}
+	return ui.DEA, false
+}
+
+func (cmd *MigrateApps) journaled(appPrinter *displayhelpers.AppPrinter, state JournalState) bool {
+	if cmd.Journal == nil {
+		return false
+	}
+
+	journaledState, _ := cmd.Journal.State(appPrinter.App.Guid)
+	return journaledState == state
+}
+
+func (cmd *MigrateApps) record(appPrinter *displayhelpers.AppPrinter, state JournalState) {
+	if cmd.Journal == nil {
+		return
+	}
+
+	cmd.abandonedMutex.Lock()
+	abandoned := cmd.abandoned
+	cmd.abandonedMutex.Unlock()
+	if abandoned {
+		return
+	}
+
+	err := cmd.Journal.Record(appPrinter.App, state)
+	if err != nil {
+		cmd.MigrateAppsCommand.JournalWarning(err)
+	}
 }
 
-func (cmd *MigrateApps) migrateApps(cliConnection api.Connection, apps models.Applications, spaceMap map[string]models.Space, maxInFlight int) (int, int) {
+func (cmd *MigrateApps) migrateApps(cliConnection api.Connection, apps models.Applications, spaceMap map[string]models.Space, maxInFlight int, stop <-chan struct{}) []migrationResult {
 	if len(apps) < maxInFlight {
 		maxInFlight = len(apps)
 	}
 
-	runningAppsChan := generateAppsChan(apps)
-	outputsChan, waitDone := processAppsChan(cliConnection, spaceMap, cmd.MigrateApp, runningAppsChan, maxInFlight, len(apps))
+	scheduler := NewScheduler(apps, spaceMap, cmd.Limits, cmd.RouteGroups)
+	budget := NewMemoryBudget(cmd.MaxMemoryInFlight)
+	hold := func(app *displayhelpers.AppPrinter, held models.Applications) {
+		cmd.heldMutex.Lock()
+		cmd.held = append(cmd.held, held...)
+		cmd.heldMutex.Unlock()
+
+		var heldPrinters []ui.ApplicationPrinter
+		for _, heldApp := range held {
+			heldPrinters = append(heldPrinters, &displayhelpers.AppPrinter{
+				App:    heldApp,
+				Spaces: spaceMap,
+			})
+		}
+		cmd.MigrateAppsCommand.HoldRouteGroup(app, heldPrinters)
+	}
+
+	var (
+		started      []*displayhelpers.AppPrinter
+		startedMutex sync.Mutex
+	)
+	migrate := func(appPrinter *displayhelpers.AppPrinter, diegoSupport DiegoFlagSetter, appStatus AppStatusGetter) MigrationResult {
+		startedMutex.Lock()
+		started = append(started, appPrinter)
+		startedMutex.Unlock()
+
+		return cmd.MigrateApp(appPrinter, diegoSupport, appStatus)
+	}
+	outputsChan, waitDone := processApps(cliConnection, spaceMap, migrate, hold, scheduler, maxInFlight, budget, stop, len(apps))
+
+	done := make(chan struct{})
+	go func() {
+		waitDone.Wait()
+		close(done)
+	}()
+
+	// After an interrupt, apps being migrated are given their own startup
+	// timeout to finish, and are recorded as failed when they do not.
+	startedApps := func() []*displayhelpers.AppPrinter {
+		startedMutex.Lock()
+		defer startedMutex.Unlock()
+		return append([]*displayhelpers.AppPrinter(nil), started...)
+	}
+	grace := func() time.Duration {
+		var longest time.Duration
+		for _, appPrinter := range startedApps() {
+			if startup := cmd.targetFor(appPrinter).Timeouts.Startup; startup > longest {
+				longest = startup
+			}
+		}
+		return longest
+	}
+	if !WaitAfterInterrupt(done, stop, grace) {
+		return cmd.abandon(startedApps(), outputsChan)
+	}
 
-	waitDone.Wait()
 	close(outputsChan)
 
 	return outputAppsChan(outputsChan)
 }
 
-func generateAppsChan(apps models.Applications) chan models.Application {
-	runningAppsChan := make(chan models.Application)
-	go func() {
-		defer close(runningAppsChan)
-		for _, app := range apps {
-			runningAppsChan <- app
+// abandon stops waiting for the started apps that have not finished
+// migrating, and records them as failed. The results of the apps that did
+// finish are read from outputsChan, which is left open for the abandoned ones.
+func (cmd *MigrateApps) abandon(started []*displayhelpers.AppPrinter, outputsChan chan migrationResult) []migrationResult {
+	cmd.abandonedMutex.Lock()
+	cmd.abandoned = true
+	cmd.abandonedMutex.Unlock()
+
+	var results []migrationResult
+	finished := map[string]bool{}
+	for len(outputsChan) > 0 {
+		result := <-outputsChan
+		finished[result.AppPrinter.App.Guid] = true
+		results = append(results, result)
+	}
+
+	for _, appPrinter := range started {
+		if finished[appPrinter.App.Guid] {
+			continue
 		}
-	}()
 
-	return runningAppsChan
+		cmd.MigrateAppsCommand.AbandonedEach(appPrinter)
+		if cmd.Journal != nil {
+			err := cmd.Journal.Record(appPrinter.App, JournalFailed)
+			if err != nil {
+				cmd.MigrateAppsCommand.JournalWarning(err)
+			}
+		}
+
+		previousRuntime, _ := cmd.previousRuntime(appPrinter, cmd.targetFor(appPrinter))
+		results = append(results, migrationResult{
+			MigrationResult: MigrationResult{
+				Outcome:         TimedOut,
+				Err:             AbandonedError{},
+				Attempts:        1,
+				PreviousRuntime: previousRuntime,
+			},
+			AppPrinter: appPrinter,
+		})
+	}
+
+	return results
 }
 
-func processAppsChan(
+// processApps migrates the apps the scheduler hands out until it runs out of
+// apps or stop is closed. When an app fails to migrate, the rest of its route
+// group is held back and passed to hold.
+func processApps(
 	cliConnection api.Connection,
 	spaceMap map[string]models.Space,
 	migrate migrateAppFunc,
-	appsChan chan models.Application,
+	hold func(*displayhelpers.AppPrinter, models.Applications),
+	scheduler *Scheduler,
 	maxInFlight int,
-	outputSize int) (chan int, *sync.WaitGroup) {
+	budget *MemoryBudget,
+	stop <-chan struct{},
+	outputSize int) (chan migrationResult, *sync.WaitGroup) {
 	var waitDone sync.WaitGroup
 
-	output := make(chan int, outputSize)
+	output := make(chan migrationResult, outputSize)
 
 	diegoSupport := diegosupport.NewDiegoSupport(cliConnection)
+	appStatus := cloudcontroller.NewClient(cliConnection)
 
 	for i := 0; i < maxInFlight; i++ {
 		waitDone.Add(1)
@@ -201,30 +656,78 @@ func processAppsChan(
 		go func() {
 			defer waitDone.Done()
 
-			for app := range appsChan {
+			for {
+				app, ok := scheduler.Next(stop)
+				if !ok {
+					return
+				}
+
+				memory := app.StartedMemory()
+				if !budget.Acquire(memory, stop) {
+					scheduler.Done(app)
+					return
+				}
+
 				a := &displayhelpers.AppPrinter{
 					App:    app,
 					Spaces: spaceMap,
 				}
-				output <- migrate(a, diegoSupport)
+				start := time.Now()
+				result := migrate(a, diegoSupport, appStatus)
+				budget.Release(memory)
+				if result.Outcome != Success && result.Outcome != Warning {
+					if held := scheduler.Hold(app); len(held) > 0 {
+						hold(a, held)
+					}
+				}
+				scheduler.Done(app)
+
+				output <- migrationResult{
+					MigrationResult: result,
+					AppPrinter:      a,
+					Duration:        time.Since(start),
+				}
 			}
 		}()
 	}
 	return output, &waitDone
 }
 
-func outputAppsChan(outputsChan chan int) (int, int) {
-	warnings := 0
-	errors := 0
+func outputAppsChan(outputsChan chan migrationResult) []migrationResult {
+	var results []migrationResult
 
 	for result := range outputsChan {
-		switch result {
+		results = append(results, result)
+	}
+	return results
+}
+
+func summarize(results []migrationResult) ui.MigrationSummary {
+	var summary ui.MigrationSummary
+
+	for _, result := range results {
+		summary.Attempts++
+
+		if result.Attempts > 1 {
+			summary.Retried = append(summary.Retried, ui.AppAttempts{
+				App:      result.AppPrinter,
+				Attempts: result.Attempts,
+			})
+		}
+
+		switch result.Outcome {
 		case Warning:
-			warnings++
-		case Err:
-			errors++
+			summary.Warnings++
+		case Err, StagingFailed, Crashed, TimedOut:
+			summary.Errors++
+		case RolledBack:
+			summary.RolledBack = append(summary.RolledBack, result.AppPrinter)
+		case RollbackFailed:
+			summary.RollbackFailed = append(summary.RollbackFailed, result.AppPrinter)
+		case Degraded:
+			summary.Degraded = append(summary.Degraded, result.AppPrinter)
 		default:
 		}
 	}
-	return warnings, errors
+	return summary
 }
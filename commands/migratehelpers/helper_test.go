@@ -3,11 +3,16 @@ package migratehelpers_test
 import (
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
 	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
 	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
 	"github.com/cloudfoundry-incubator/diego-enabler/models"
 	"github.com/cloudfoundry-incubator/diego-enabler/ui"
 
@@ -22,56 +27,70 @@ var _ = Describe("MigrateApps", func() {
 	)
 
 	Describe("MigrateApp", func() {
-		Context("when migrating the app fails", func() {
-			var (
-				success      int
-				diegoSupport *migratehelpersfakes.FakeDiegoFlagSetter
-				appPrinter   *displayhelpers.AppPrinter
-				buf          *gbytes.Buffer
-				stdout       *os.File
-			)
+		var (
+			success      int
+			migrateErr   error
+			attempts     int
+			previous     ui.Runtime
+			expected     int
+			diegoSupport *migratehelpersfakes.FakeDiegoFlagSetter
+			appStatus    *migratehelpersfakes.FakeAppStatusGetter
+			appPrinter   *displayhelpers.AppPrinter
+			buf          *gbytes.Buffer
+			stdout       *os.File
+		)
 
-			BeforeEach(func() {
-				buf = gbytes.NewBuffer()
-				stdout = captureStdout(buf)
-
-				diegoSupport = new(migratehelpersfakes.FakeDiegoFlagSetter)
-				appPrinter = &displayhelpers.AppPrinter{
-					App: models.Application{
-						ApplicationEntity: models.ApplicationEntity{
-							Name:      "some-app",
-							Diego:     true,
-							State:     "STARTED",
-							SpaceGuid: "some-space-guid",
-						},
-						ApplicationMetadata: models.ApplicationMetadata{
-							Guid: "some-app-guid",
-						},
+		BeforeEach(func() {
+			buf = gbytes.NewBuffer()
+			stdout = captureStdout(buf)
+
+			diegoSupport = new(migratehelpersfakes.FakeDiegoFlagSetter)
+			appStatus = new(migratehelpersfakes.FakeAppStatusGetter)
+			appPrinter = &displayhelpers.AppPrinter{
+				App: models.Application{
+					ApplicationEntity: models.ApplicationEntity{
+						Name:      "some-app",
+						Diego:     true,
+						State:     "STARTED",
+						SpaceGuid: "some-space-guid",
 					},
-					Spaces: map[string]models.Space{},
-				}
-				command = MigrateApps{
-					MaxInFlight:    1,
-					Runtime:        ui.Diego,
-					AppsGetterFunc: nil,
-					MigrateAppsCommand: &ui.MigrateAppsCommand{
-						Username:     "some-username",
-						Runtime:      ui.Diego,
-						Organization: "some-organization",
-						Space:        "some-space",
+					ApplicationMetadata: models.ApplicationMetadata{
+						Guid: "some-app-guid",
 					},
-				}
-			})
+				},
+				Spaces: map[string]models.Space{},
+			}
+			command = MigrateApps{
+				MaxInFlight:    1,
+				Runtime:        ui.Diego,
+				AppsGetterFunc: nil,
+				MigrateAppsCommand: &ui.MigrateAppsCommand{
+					Username:     "some-username",
+					Runtime:      ui.Diego,
+					Organization: "some-organization",
+					Space:        "some-space",
+				},
+				Timeouts: Timeouts{
+					Staging: 50 * time.Millisecond,
+					Startup: 50 * time.Millisecond,
+				},
+				PollInterval: time.Millisecond,
+			}
+		})
 
-			AfterEach(func() {
-				os.Stdout.Close()
-				os.Stdout = stdout
-			})
+		AfterEach(func() {
+			os.Stdout.Close()
+			os.Stdout = stdout
+		})
 
-			JustBeforeEach(func() {
-				success = command.MigrateApp(appPrinter, diegoSupport)
-			})
+		JustBeforeEach(func() {
+			result := command.MigrateApp(appPrinter, diegoSupport, appStatus)
+			success, migrateErr, attempts = result.Outcome, result.Err, result.Attempts
+			previous = result.PreviousRuntime
+			expected = result.ExpectedRunning
+		})
 
+		Context("when migrating the app fails", func() {
 			Context("when the user does not have permissions to migrate apps", func() {
 				BeforeEach(func() {
 					diegoSupport.SetDiegoFlagReturns(nil, errors.New("CF-NotAuthorized - You are not authorized to perform the requested action"))
@@ -79,6 +98,7 @@ var _ = Describe("MigrateApps", func() {
 
 				It("returns a warning", func() {
 					Expect(success).To(Equal(Warning))
+					Expect(migrateErr).To(HaveOccurred())
 					Eventually(buf).Should(gbytes.Say("WARNING"))
 				})
 			})
@@ -90,9 +110,548 @@ var _ = Describe("MigrateApps", func() {
 
 				It("returns an error", func() {
 					Expect(success).To(Equal(Err))
+					Expect(attempts).To(Equal(1))
 					Eventually(buf).Should(gbytes.Say("Error: Failed to migrate app"))
 				})
 			})
+
+			Context("when the failure is transient", func() {
+				BeforeEach(func() {
+					appPrinter.App.State = models.Stopped
+					command.Retry = Retry{Retries: 2, Wait: time.Millisecond}
+
+					calls := 0
+					diegoSupport.SetDiegoFlagStub = func(string, bool) ([]string, error) {
+						calls++
+						if calls == 1 {
+							return nil, diegosupport.DiegoError{ErrorCode: "CF-ServiceUnavailable"}
+						}
+						return nil, nil
+					}
+				})
+
+				It("retries and counts the attempts", func() {
+					Expect(success).To(Equal(Success))
+					Expect(attempts).To(Equal(2))
+					Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(2))
+				})
+			})
+		})
+
+		Context("when journaling the migration", func() {
+			var (
+				dir     string
+				journal *Journal
+			)
+
+			BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "journal")
+				Expect(err).NotTo(HaveOccurred())
+
+				journal = NewJournal(filepath.Join(dir, "migration.json"), ui.Diego)
+				command.Journal = journal
+				appPrinter.App.State = models.Stopped
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			It("records the app as verified", func() {
+				Expect(success).To(Equal(Success))
+
+				state, _ := journal.State("some-app-guid")
+				Expect(state).To(Equal(JournalVerified))
+			})
+
+			Context("when the migration fails", func() {
+				BeforeEach(func() {
+					diegoSupport.SetDiegoFlagReturns(nil, errors.New("disaster"))
+				})
+
+				It("records the app as failed", func() {
+					Expect(success).To(Equal(Err))
+
+					state, _ := journal.State("some-app-guid")
+					Expect(state).To(Equal(JournalFailed))
+				})
+			})
+
+			Context("when a previous run flipped the app", func() {
+				BeforeEach(func() {
+					Expect(journal.Record(appPrinter.App, JournalFlipped)).To(Succeed())
+				})
+
+				It("verifies the app without flipping it again", func() {
+					Expect(success).To(Equal(Success))
+					Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(0))
+				})
+
+				It("returns the runtime the app ran on before it was flipped", func() {
+					Expect(previous).To(Equal(ui.DEA))
+				})
+			})
+
+			Context("when a previous run failed to verify the app after flipping it", func() {
+				BeforeEach(func() {
+					Expect(journal.Record(appPrinter.App, JournalFailed)).To(Succeed())
+				})
+
+				It("verifies the app without flipping it again", func() {
+					Expect(success).To(Equal(Success))
+					Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(0))
+					Expect(previous).To(Equal(ui.DEA))
+				})
+			})
+		})
+
+		Context("when the app is stopped", func() {
+			BeforeEach(func() {
+				appPrinter.App.State = models.Stopped
+			})
+
+			It("returns the runtime the app ran on", func() {
+				Expect(previous).To(Equal(ui.Diego))
+			})
+
+			It("does not wait for the app to start", func() {
+				Expect(success).To(Equal(Success))
+				Expect(appStatus.GetAppCallCount()).To(Equal(0))
+				Expect(appStatus.GetAppInstancesCallCount()).To(Equal(0))
+				Eventually(buf).Should(gbytes.Say("Completed migrating app"))
+			})
+		})
+
+		Context("when the app is started", func() {
+			var stagedApp models.Application
+
+			BeforeEach(func() {
+				appPrinter.App.Instances = 2
+
+				stagedApp = appPrinter.App
+				stagedApp.PackageState = models.PackageStaged
+				appStatus.GetAppReturns(stagedApp, nil)
+			})
+
+			Context("when all instances are running", func() {
+				BeforeEach(func() {
+					appStatus.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceRunning},
+						"1": {State: models.InstanceRunning},
+					}, nil)
+				})
+
+				It("polls the app by guid and succeeds", func() {
+					Expect(success).To(Equal(Success))
+					Expect(appStatus.GetAppArgsForCall(0)).To(Equal("some-app-guid"))
+					Expect(appStatus.GetAppInstancesArgsForCall(0)).To(Equal("some-app-guid"))
+					Eventually(buf).Should(gbytes.Say("Completed migrating app"))
+				})
+
+				Context("when the app still reports the package it was staged with before the flip", func() {
+					BeforeEach(func() {
+						appPrinter.App.PackageState = models.PackageStaged
+					})
+
+					It("keeps polling the app before checking its instances", func() {
+						Expect(success).To(Equal(Success))
+						Expect(appStatus.GetAppCallCount()).To(BeNumerically(">", 1))
+					})
+				})
+
+				Context("when showing progress line by line", func() {
+					BeforeEach(func() {
+						command.MigrateAppsCommand.Progress = ui.LineProgress{}
+					})
+
+					It("prefixes every line with the app", func() {
+						Expect(success).To(Equal(Success))
+						Eventually(buf).Should(gbytes.Say(`\[ / some-space-guid / some-app\] Migrating app`))
+						Eventually(buf).Should(gbytes.Say(`\[ / some-space-guid / some-app\] switching runtime`))
+						Eventually(buf).Should(gbytes.Say(`\[ / some-space-guid / some-app\] starting instances`))
+						Eventually(buf).Should(gbytes.Say(`\[ / some-space-guid / some-app\] Completed migrating app`))
+					})
+				})
+			})
+
+			Context("when the instances are still starting", func() {
+				BeforeEach(func() {
+					calls := 0
+					appStatus.GetAppInstancesStub = func(string) (models.AppInstances, error) {
+						calls++
+						if calls > 1 && calls < 4 {
+							return models.AppInstances{
+								"0": {State: models.InstanceRunning},
+								"1": {State: models.InstanceStarting},
+							}, nil
+						}
+						return models.AppInstances{
+							"0": {State: models.InstanceRunning},
+							"1": {State: models.InstanceRunning},
+						}, nil
+					}
+				})
+
+				It("keeps polling until they are running", func() {
+					Expect(success).To(Equal(Success))
+					Expect(appStatus.GetAppInstancesCallCount()).To(Equal(4))
+				})
+			})
+
+			Context("when staging is still in progress", func() {
+				BeforeEach(func() {
+					pendingApp := stagedApp
+					pendingApp.PackageState = models.PackagePending
+
+					calls := 0
+					appStatus.GetAppStub = func(string) (models.Application, error) {
+						calls++
+						if calls < 2 {
+							return pendingApp, nil
+						}
+						return stagedApp, nil
+					}
+					appStatus.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceRunning},
+						"1": {State: models.InstanceRunning},
+					}, nil)
+				})
+
+				It("waits for staging to finish", func() {
+					Expect(success).To(Equal(Success))
+					Expect(appStatus.GetAppCallCount()).To(Equal(2))
+				})
+			})
+
+			Context("when staging fails", func() {
+				BeforeEach(func() {
+					failedApp := stagedApp
+					failedApp.PackageState = models.PackageFailed
+					failedApp.StagingFailedReason = "NoAppDetectedError"
+					failedApp.StagingFailedDescription = "An app was not successfully detected by any available buildpack"
+					appStatus.GetAppReturns(failedApp, nil)
+				})
+
+				It("returns a staging failure with the reason", func() {
+					Expect(success).To(Equal(StagingFailed))
+					Expect(migrateErr).To(MatchError("staging failed: NoAppDetectedError - An app was not successfully detected by any available buildpack"))
+					Expect(appStatus.GetAppInstancesCallCount()).To(Equal(1))
+					Eventually(buf).Should(gbytes.Say("failed to stage on Diego: staging failed: NoAppDetectedError"))
+				})
+			})
+
+			Context("when the instances endpoint reports a staging error", func() {
+				BeforeEach(func() {
+					appStatus.GetAppInstancesReturns(nil, cloudcontroller.Error{
+						Code:        170004,
+						Description: "App staging failed",
+						ErrorCode:   cloudcontroller.StagingErrorErrorCode,
+					})
+				})
+
+				It("returns a staging failure with the reason", func() {
+					Expect(success).To(Equal(StagingFailed))
+					Expect(migrateErr).To(BeAssignableToTypeOf(StagingError{}))
+				})
+			})
+
+			Context("when all instances are crashing", func() {
+				BeforeEach(func() {
+					appStatus.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceCrashed},
+						"1": {State: models.InstanceFlapping},
+					}, nil)
+				})
+
+				It("returns a crash", func() {
+					Expect(success).To(Equal(Crashed))
+					Eventually(buf).Should(gbytes.Say("is crashing"))
+				})
+			})
+
+			Context("when fewer instances are running than before until the timeout", func() {
+				BeforeEach(func() {
+					calls := 0
+					appStatus.GetAppInstancesStub = func(string) (models.AppInstances, error) {
+						calls++
+						if calls == 1 {
+							return models.AppInstances{
+								"0": {State: models.InstanceRunning},
+								"1": {State: models.InstanceRunning},
+							}, nil
+						}
+						return models.AppInstances{
+							"0": {State: models.InstanceRunning},
+							"1": {State: models.InstanceCrashed},
+						}, nil
+					}
+				})
+
+				It("returns degraded", func() {
+					Expect(success).To(Equal(Degraded))
+					Expect(migrateErr).To(Equal(DegradedError{Running: 1, Expected: 2}))
+					Eventually(buf).Should(gbytes.Say("is running 1 of the 2 instances it ran before"))
+				})
+			})
+
+			Context("when fewer instances than desired were running before the migration", func() {
+				BeforeEach(func() {
+					appStatus.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceRunning},
+						"1": {State: models.InstanceCrashed},
+					}, nil)
+				})
+
+				It("succeeds once as many instances are running", func() {
+					Expect(success).To(Equal(Success))
+					Expect(expected).To(Equal(1))
+				})
+			})
+
+			Context("when the instances do not start before the timeout", func() {
+				BeforeEach(func() {
+					appStatus.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceStarting},
+						"1": {State: models.InstanceStarting},
+					}, nil)
+				})
+
+				It("times out", func() {
+					Expect(success).To(Equal(TimedOut))
+					Eventually(buf).Should(gbytes.Say("Timed out waiting for app"))
+				})
+			})
+
+			Context("when polling the app fails transiently", func() {
+				BeforeEach(func() {
+					command.Retry = Retry{Retries: 2, Wait: time.Millisecond}
+
+					calls := 0
+					appStatus.GetAppStub = func(string) (models.Application, error) {
+						calls++
+						if calls == 1 {
+							return models.Application{}, cloudcontroller.Error{ErrorCode: "CF-ServiceUnavailable"}
+						}
+						return stagedApp, nil
+					}
+					appStatus.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceRunning},
+						"1": {State: models.InstanceRunning},
+					}, nil)
+				})
+
+				It("retries and succeeds", func() {
+					Expect(success).To(Equal(Success))
+					Expect(appStatus.GetAppCallCount()).To(Equal(2))
+				})
+			})
+
+			Context("when polling the app fails", func() {
+				BeforeEach(func() {
+					appStatus.GetAppReturns(models.Application{}, errors.New("network down"))
+				})
+
+				It("returns an error", func() {
+					Expect(success).To(Equal(Err))
+					Eventually(buf).Should(gbytes.Say("Failed to verify app"))
+				})
+			})
+
+			Context("when rolling back on failure", func() {
+				BeforeEach(func() {
+					command.RollbackOnFailure = true
+					appPrinter.App.Diego = false
+
+					calls := 0
+					appStatus.GetAppInstancesStub = func(string) (models.AppInstances, error) {
+						calls++
+						switch calls {
+						case 1:
+							return models.AppInstances{
+								"0": {State: models.InstanceRunning},
+								"1": {State: models.InstanceRunning},
+							}, nil
+						case 2:
+							return models.AppInstances{
+								"0": {State: models.InstanceCrashed},
+								"1": {State: models.InstanceCrashed},
+							}, nil
+						default:
+							return models.AppInstances{
+								"0": {State: models.InstanceRunning},
+								"1": {State: models.InstanceRunning},
+							}, nil
+						}
+					}
+				})
+
+				It("moves the app back to its original runtime", func() {
+					Expect(success).To(Equal(RolledBack))
+					Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(2))
+
+					guid, diego := diegoSupport.SetDiegoFlagArgsForCall(0)
+					Expect(guid).To(Equal("some-app-guid"))
+					Expect(diego).To(BeTrue())
+
+					guid, diego = diegoSupport.SetDiegoFlagArgsForCall(1)
+					Expect(guid).To(Equal("some-app-guid"))
+					Expect(diego).To(BeFalse())
+
+					Eventually(buf).Should(gbytes.Say("Rolling back app"))
+					Eventually(buf).Should(gbytes.Say("Completed rolling back app"))
+				})
+
+				Context("when the app does not recover on the original runtime", func() {
+					BeforeEach(func() {
+						calls := 0
+						appStatus.GetAppInstancesStub = func(string) (models.AppInstances, error) {
+							calls++
+							if calls == 1 {
+								return models.AppInstances{
+									"0": {State: models.InstanceRunning},
+									"1": {State: models.InstanceRunning},
+								}, nil
+							}
+							return models.AppInstances{
+								"0": {State: models.InstanceCrashed},
+								"1": {State: models.InstanceCrashed},
+							}, nil
+						}
+					})
+
+					It("reports the failed rollback", func() {
+						Expect(success).To(Equal(RollbackFailed))
+						Expect(migrateErr).To(MatchError("instances crashed; rollback failed: app did not recover on DEA"))
+						Eventually(buf).Should(gbytes.Say("Failed to roll back app"))
+					})
+				})
+
+				Context("when the app ran no instances before the migration and does not recover", func() {
+					BeforeEach(func() {
+						appStatus.GetAppInstancesStub = nil
+						appStatus.GetAppInstancesReturns(models.AppInstances{
+							"0": {State: models.InstanceStarting},
+							"1": {State: models.InstanceStarting},
+						}, nil)
+					})
+
+					It("reports the failed rollback", func() {
+						Expect(success).To(Equal(RollbackFailed))
+						Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(2))
+						Eventually(buf).Should(gbytes.Say("Failed to roll back app"))
+					})
+				})
+
+				Context("when setting the flag back fails", func() {
+					BeforeEach(func() {
+						calls := 0
+						diegoSupport.SetDiegoFlagStub = func(string, bool) ([]string, error) {
+							calls++
+							if calls == 1 {
+								return nil, nil
+							}
+							return nil, errors.New("disaster")
+						}
+					})
+
+					It("reports the failed rollback", func() {
+						Expect(success).To(Equal(RollbackFailed))
+						Eventually(buf).Should(gbytes.Say("Failed to roll back app"))
+					})
+				})
+
+				Context("when the app was already on the target runtime", func() {
+					BeforeEach(func() {
+						appPrinter.App.Diego = true
+					})
+
+					It("leaves the app on the runtime it was on", func() {
+						Expect(success).To(Equal(Crashed))
+						Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when the migration succeeds", func() {
+					BeforeEach(func() {
+						appStatus.GetAppInstancesReturns(models.AppInstances{
+							"0": {State: models.InstanceRunning},
+							"1": {State: models.InstanceRunning},
+						}, nil)
+					})
+
+					It("does not roll back", func() {
+						Expect(success).To(Equal(Success))
+						Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			Context("when migrating blue-green", func() {
+				var appCloner *migratehelpersfakes.FakeAppCloner
+
+				BeforeEach(func() {
+					appCloner = new(migratehelpersfakes.FakeAppCloner)
+					appCloner.CreateAppReturns(models.Application{
+						ApplicationMetadata: models.ApplicationMetadata{Guid: "some-copy-guid"},
+					}, nil)
+					appCloner.GetDropletReturns(models.Droplet{State: models.DropletStaged}, nil)
+					appCloner.GetAppReturns(stagedApp, nil)
+					appCloner.GetAppInstancesReturns(models.AppInstances{
+						"0": {State: models.InstanceRunning},
+						"1": {State: models.InstanceRunning},
+					}, nil)
+
+					command.BlueGreen = true
+					command.AppCloner = appCloner
+				})
+
+				It("replaces the app with a copy instead of flipping its flag", func() {
+					Expect(success).To(Equal(Success))
+					Expect(diegoSupport.SetDiegoFlagCallCount()).To(Equal(0))
+					Expect(appCloner.CreateAppCallCount()).To(Equal(1))
+					Expect(appCloner.DeleteAppArgsForCall(0)).To(Equal("some-app-guid"))
+					Eventually(buf).Should(gbytes.Say("Completed migrating app"))
+				})
+
+				Context("when the copy does not start", func() {
+					BeforeEach(func() {
+						appCloner.GetAppInstancesReturns(models.AppInstances{
+							"0": {State: models.InstanceCrashed},
+						}, nil)
+					})
+
+					It("reports the app as rolled back", func() {
+						Expect(success).To(Equal(RolledBack))
+						Expect(appCloner.DeleteAppArgsForCall(0)).To(Equal("some-copy-guid"))
+						Eventually(buf).Should(gbytes.Say("the original app was restored"))
+					})
+				})
+
+				Context("when the routes cannot be fetched", func() {
+					BeforeEach(func() {
+						appCloner.GetAppRoutesReturns(nil, errors.New("routes failed"))
+					})
+
+					It("reports a failure rather than a rollback", func() {
+						Expect(success).To(Equal(Err))
+						Expect(appCloner.CreateAppCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the original app cannot be restored", func() {
+					BeforeEach(func() {
+						appCloner.GetAppInstancesReturns(models.AppInstances{
+							"0": {State: models.InstanceCrashed},
+						}, nil)
+						appCloner.DeleteAppReturns(errors.New("delete failed"))
+					})
+
+					It("reports the rollback as failed", func() {
+						Expect(success).To(Equal(RollbackFailed))
+					})
+				})
+			})
 		})
 	})
 })
@@ -0,0 +1,129 @@
+package migratehelpers
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ForcedExitCode is the exit status when a second interrupt stops the
+// migration without waiting for in-flight apps.
+const ForcedExitCode = 130
+
+type InterruptedError struct {
+	NotAttempted int
+	Journal      string
+}
+
+func (e InterruptedError) Error() string {
+	message := fmt.Sprintf("Migration interrupted; %d apps were not migrated", e.NotAttempted)
+	if e.Journal != "" && e.NotAttempted > 0 {
+		message += fmt.Sprintf(". Run again with --resume %s to migrate them", e.Journal)
+	}
+
+	return message
+}
+
+// AbandonedError is the error of an app that was still being migrated when
+// the wait for it after an interrupt ran out.
+type AbandonedError struct{}
+
+func (e AbandonedError) Error() string {
+	return "Stopped waiting for the app to migrate after the interrupt"
+}
+
+// Interrupter stops dispatching apps on the first signal and calls onForce on
+// the second.
+type Interrupter struct {
+	stopped chan struct{}
+	done    chan struct{}
+}
+
+func WatchInterrupts(signals <-chan os.Signal, onInterrupt func(), onForce func()) *Interrupter {
+	i := &Interrupter{
+		stopped: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case <-signals:
+		case <-i.done:
+			return
+		}
+
+		onInterrupt()
+		close(i.stopped)
+
+		select {
+		case <-signals:
+			onForce()
+		case <-i.done:
+		}
+	}()
+
+	return i
+}
+
+// Stopped is closed once no more apps should be dispatched.
+func (i *Interrupter) Stopped() <-chan struct{} {
+	return i.stopped
+}
+
+func (i *Interrupter) Interrupted() bool {
+	select {
+	case <-i.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Confirm returns the answer to confirm, or false as soon as the migration is
+// interrupted. The prompt is left waiting for input the user will not give,
+// which is harmless as the migration stops.
+func (i *Interrupter) Confirm(confirm func() bool) bool {
+	confirmed := make(chan bool, 1)
+	go func() {
+		confirmed <- confirm()
+	}()
+
+	select {
+	case ok := <-confirmed:
+		return ok
+	case <-i.stopped:
+		return false
+	}
+}
+
+// Close stops watching for signals.
+func (i *Interrupter) Close() {
+	close(i.done)
+}
+
+// WaitAfterInterrupt waits for done. Once stop is closed, it waits at most
+// grace longer, and reports whether done was closed in time. grace is only
+// called once stop is closed.
+func WaitAfterInterrupt(done <-chan struct{}, stop <-chan struct{}, grace func() time.Duration) bool {
+	select {
+	case <-done:
+		return true
+	case <-stop:
+	}
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(grace()):
+		return false
+	}
+}
+
+func notifyInterrupts() (chan os.Signal, func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	return signals, func() { signal.Stop(signals) }
+}
@@ -0,0 +1,140 @@
+package migratehelpers_test
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interrupter", func() {
+	var (
+		signals     chan os.Signal
+		interrupts  chan struct{}
+		forced      chan struct{}
+		interrupter *Interrupter
+	)
+
+	BeforeEach(func() {
+		signals = make(chan os.Signal, 2)
+		interrupts = make(chan struct{}, 1)
+		forced = make(chan struct{}, 1)
+
+		interrupter = WatchInterrupts(signals, func() {
+			interrupts <- struct{}{}
+		}, func() {
+			forced <- struct{}{}
+		})
+	})
+
+	AfterEach(func() {
+		interrupter.Close()
+	})
+
+	It("is not interrupted until a signal arrives", func() {
+		Expect(interrupter.Interrupted()).To(BeFalse())
+		Consistently(interrupter.Stopped()).ShouldNot(BeClosed())
+	})
+
+	Context("when a signal arrives", func() {
+		BeforeEach(func() {
+			signals <- os.Interrupt
+		})
+
+		It("stops dispatching without forcing an exit", func() {
+			Eventually(interrupter.Stopped()).Should(BeClosed())
+			Expect(interrupter.Interrupted()).To(BeTrue())
+			Expect(interrupts).To(Receive())
+			Consistently(forced).ShouldNot(Receive())
+		})
+
+		Context("when a second signal arrives", func() {
+			BeforeEach(func() {
+				signals <- syscall.SIGTERM
+			})
+
+			It("forces an exit", func() {
+				Eventually(forced).Should(Receive())
+			})
+		})
+	})
+
+	Describe("Confirm", func() {
+		It("returns the answer", func() {
+			Expect(interrupter.Confirm(func() bool { return true })).To(BeTrue())
+			Expect(interrupter.Confirm(func() bool { return false })).To(BeFalse())
+		})
+
+		Context("when a signal arrives while waiting for the answer", func() {
+			It("returns false without waiting for the answer", func() {
+				answer := make(chan bool)
+				defer close(answer)
+
+				confirmed := make(chan bool, 1)
+				go func() {
+					confirmed <- interrupter.Confirm(func() bool { return <-answer })
+				}()
+
+				Consistently(confirmed).ShouldNot(Receive())
+				signals <- os.Interrupt
+				Eventually(confirmed).Should(Receive(BeFalse()))
+			})
+		})
+	})
+})
+
+var _ = Describe("WaitAfterInterrupt", func() {
+	var (
+		done chan struct{}
+		stop chan struct{}
+	)
+
+	BeforeEach(func() {
+		done = make(chan struct{})
+		stop = make(chan struct{})
+	})
+
+	grace := func() time.Duration {
+		return 10 * time.Millisecond
+	}
+
+	It("waits for done", func() {
+		close(done)
+		Expect(WaitAfterInterrupt(done, stop, grace)).To(BeTrue())
+	})
+
+	Context("when stopped", func() {
+		BeforeEach(func() {
+			close(stop)
+		})
+
+		It("waits for done within the grace period", func() {
+			go func() {
+				time.Sleep(time.Millisecond)
+				close(done)
+			}()
+
+			Expect(WaitAfterInterrupt(done, stop, grace)).To(BeTrue())
+		})
+
+		It("gives up once the grace period ends", func() {
+			Expect(WaitAfterInterrupt(done, stop, grace)).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("InterruptedError", func() {
+	It("reports the apps that were not migrated", func() {
+		err := InterruptedError{NotAttempted: 3}
+		Expect(err).To(MatchError("Migration interrupted; 3 apps were not migrated"))
+	})
+
+	It("suggests resuming from the journal", func() {
+		err := InterruptedError{NotAttempted: 3, Journal: "migration.json"}
+		Expect(err).To(MatchError("Migration interrupted; 3 apps were not migrated. Run again with --resume migration.json to migrate them"))
+	})
+})
@@ -0,0 +1,341 @@
+package migratehelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+type JournalState string
+
+const (
+	JournalPending  JournalState = "pending"
+	JournalFlipped  JournalState = "flipped"
+	JournalVerified JournalState = "verified"
+	JournalFailed   JournalState = "failed"
+)
+
+type JournalEntry struct {
+	Guid  string       `json:"guid"`
+	Name  string       `json:"name"`
+	State JournalState `json:"state"`
+}
+
+type journalHeader struct {
+	Runtime ui.Runtime `json:"runtime"`
+}
+
+// Journal records the progress of a migration on disk so that an interrupted
+// run can be resumed. The file holds a header line followed by one entry per
+// line, the latest entry of an app taking precedence. Every update appends and
+// syncs a single line, and once the appended lines outnumber the apps, the
+// journal is compacted by rewriting it atomically.
+type Journal struct {
+	Path    string
+	Runtime ui.Runtime
+
+	mutex    sync.Mutex
+	entries  []JournalEntry
+	index    map[string]int
+	file     *os.File
+	appended int
+}
+
+type JournalRuntimeMismatchError struct {
+	Path    string
+	Runtime ui.Runtime
+}
+
+func (e JournalRuntimeMismatchError) Error() string {
+	return fmt.Sprintf("Journal %s records a migration to %s", e.Path, e.Runtime)
+}
+
+func NewJournal(path string, runtime ui.Runtime) *Journal {
+	return &Journal{
+		Path:    path,
+		Runtime: runtime,
+		index:   map[string]int{},
+	}
+}
+
+func LoadJournal(path string, runtime ui.Runtime) (*Journal, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// A line without a trailing newline was cut short by a crash while it was
+	// appended, and is dropped.
+	lines := bytes.Split(body, []byte("\n"))
+	lines = lines[:len(lines)-1]
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("Invalid journal %s: no header", path)
+	}
+
+	var header journalHeader
+	err = json.Unmarshal(lines[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid journal %s: %s", path, err)
+	}
+
+	if header.Runtime != runtime {
+		return nil, JournalRuntimeMismatchError{Path: path, Runtime: header.Runtime}
+	}
+
+	journal := NewJournal(path, runtime)
+	for _, line := range lines[1:] {
+		var entry JournalEntry
+		err = json.Unmarshal(line, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid journal %s: %s", path, err)
+		}
+		journal.set(entry)
+	}
+
+	return journal, nil
+}
+
+func (j *Journal) State(appGuid string) (JournalState, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	i, ok := j.index[appGuid]
+	if !ok {
+		return "", false
+	}
+	return j.entries[i].State, true
+}
+
+// Guids returns the guids of all journaled apps in the given state, in the
+// order they were first recorded.
+func (j *Journal) Guids(state JournalState) []string {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var guids []string
+	for _, entry := range j.entries {
+		if entry.State == state {
+			guids = append(guids, entry.Guid)
+		}
+	}
+	return guids
+}
+
+// unverifiedGuids returns the guids of all journaled apps that were not
+// verified, in the order they were first recorded.
+func (j *Journal) unverifiedGuids() []string {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var guids []string
+	for _, entry := range j.entries {
+		if entry.State != JournalVerified {
+			guids = append(guids, entry.Guid)
+		}
+	}
+	return guids
+}
+
+// Add records every app that is not journaled yet as pending.
+func (j *Journal) Add(apps models.Applications) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, app := range apps {
+		if _, ok := j.index[app.Guid]; ok {
+			continue
+		}
+
+		j.set(JournalEntry{
+			Guid:  app.Guid,
+			Name:  app.Name,
+			State: JournalPending,
+		})
+	}
+
+	return j.compact()
+}
+
+func (j *Journal) Record(app models.Application, state JournalState) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entry := JournalEntry{
+		Guid:  app.Guid,
+		Name:  app.Name,
+		State: state,
+	}
+	j.set(entry)
+
+	if j.file == nil || j.appended >= len(j.entries) {
+		return j.compact()
+	}
+
+	return j.append(entry)
+}
+
+// Close closes the journal file. The journal reopens it on the next update.
+func (j *Journal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.close()
+}
+
+func (j *Journal) set(entry JournalEntry) {
+	if i, ok := j.index[entry.Guid]; ok {
+		j.entries[i] = entry
+		return
+	}
+
+	j.index[entry.Guid] = len(j.entries)
+	j.entries = append(j.entries, entry)
+}
+
+func (j *Journal) append(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = j.file.Write(append(line, '\n'))
+	if err == nil {
+		err = j.file.Sync()
+	}
+	if err != nil {
+		return err
+	}
+
+	j.appended++
+	return nil
+}
+
+// compact rewrites the journal with one line per app and reopens it for
+// appending.
+func (j *Journal) compact() error {
+	var body bytes.Buffer
+
+	encoder := json.NewEncoder(&body)
+	err := encoder.Encode(journalHeader{Runtime: j.Runtime})
+	for _, entry := range j.entries {
+		if err != nil {
+			break
+		}
+		err = encoder.Encode(entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The open file is the one the rename replaces.
+	j.close()
+
+	err = WriteFileAtomically(j.Path, body.Bytes())
+	if err != nil {
+		return err
+	}
+
+	j.file, err = os.OpenFile(j.Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+
+	j.appended = 0
+	return nil
+}
+
+func (j *Journal) close() error {
+	if j.file == nil {
+		return nil
+	}
+
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// WriteFileAtomically writes to a temporary file next to path and renames it
+// into place, so readers never observe a partially written file.
+func WriteFileAtomically(path string, body []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(body)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Resume drops the apps the journal has already verified and adds back every
+// other journaled app that is no longer listed, such as apps that were
+// flipped, or failed after they were flipped, since those are no longer
+// reported on the runtime being migrated from. Apps that were deleted since
+// are left out. The remaining apps are returned in the order the journaled run
+// migrated them, followed by apps the journal does not know yet.
+func (j *Journal) Resume(apps models.Applications, appStatus AppStatusGetter) (models.Applications, error) {
+	var remaining models.Applications
+	listed := map[string]bool{}
+
+	for _, app := range apps {
+		listed[app.Guid] = true
+		if state, _ := j.State(app.Guid); state == JournalVerified {
+			continue
+		}
+		remaining = append(remaining, app)
+	}
+
+	for _, guid := range j.unverifiedGuids() {
+		if listed[guid] {
+			continue
+		}
+
+		app, err := appStatus.GetApp(guid)
+		if cloudcontroller.IsErrorCode(err, cloudcontroller.AppNotFoundErrorCode) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		remaining = append(remaining, app)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	sort.SliceStable(remaining, func(a, b int) bool {
+		indexA, journaledA := j.index[remaining[a].Guid]
+		indexB, journaledB := j.index[remaining[b].Guid]
+		if journaledA != journaledB {
+			return journaledA
+		}
+		return indexA < indexB
+	})
+
+	return remaining, nil
+}
@@ -0,0 +1,268 @@
+package migratehelpers_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Journal", func() {
+	var (
+		dir     string
+		path    string
+		journal *Journal
+		apps    models.Applications
+	)
+
+	newApp := func(guid, name string) models.Application {
+		return models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				Name: name,
+			},
+			ApplicationMetadata: models.ApplicationMetadata{
+				Guid: guid,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "journal")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "migration.json")
+		journal = NewJournal(path, ui.Diego)
+
+		apps = models.Applications{
+			newApp("guid-1", "app-1"),
+			newApp("guid-2", "app-2"),
+			newApp("guid-3", "app-3"),
+		}
+	})
+
+	AfterEach(func() {
+		journal.Close()
+		os.RemoveAll(dir)
+	})
+
+	Describe("Add", func() {
+		It("records every app as pending", func() {
+			Expect(journal.Add(apps)).To(Succeed())
+
+			Expect(journal.Guids(JournalPending)).To(Equal([]string{"guid-1", "guid-2", "guid-3"}))
+		})
+
+		It("does not reset the state of journaled apps", func() {
+			Expect(journal.Record(apps[1], JournalVerified)).To(Succeed())
+			Expect(journal.Add(apps)).To(Succeed())
+
+			state, ok := journal.State("guid-2")
+			Expect(ok).To(BeTrue())
+			Expect(state).To(Equal(JournalVerified))
+		})
+	})
+
+	Describe("Record", func() {
+		It("writes the journal to disk", func() {
+			Expect(journal.Add(apps)).To(Succeed())
+			Expect(journal.Record(apps[0], JournalFlipped)).To(Succeed())
+			Expect(journal.Record(apps[2], JournalFailed)).To(Succeed())
+
+			loaded, err := LoadJournal(path, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(loaded.Guids(JournalFlipped)).To(Equal([]string{"guid-1"}))
+			Expect(loaded.Guids(JournalPending)).To(Equal([]string{"guid-2"}))
+			Expect(loaded.Guids(JournalFailed)).To(Equal([]string{"guid-3"}))
+		})
+
+		It("leaves no temporary files behind", func() {
+			Expect(journal.Add(apps)).To(Succeed())
+			Expect(journal.Record(apps[0], JournalVerified)).To(Succeed())
+
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+			Expect(files[0].Name()).To(Equal("migration.json"))
+		})
+
+		lines := func() []string {
+			body, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			return strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+		}
+
+		It("appends a line per update", func() {
+			Expect(journal.Add(apps)).To(Succeed())
+			Expect(lines()).To(HaveLen(4))
+
+			Expect(journal.Record(apps[0], JournalFlipped)).To(Succeed())
+			Expect(lines()).To(HaveLen(5))
+			Expect(lines()[4]).To(Equal(`{"guid":"guid-1","name":"app-1","state":"flipped"}`))
+		})
+
+		It("compacts the journal once the appended lines outnumber the apps", func() {
+			Expect(journal.Add(apps)).To(Succeed())
+			for _, app := range apps {
+				Expect(journal.Record(app, JournalFlipped)).To(Succeed())
+			}
+			Expect(lines()).To(HaveLen(7))
+
+			Expect(journal.Record(apps[0], JournalVerified)).To(Succeed())
+			Expect(lines()).To(HaveLen(4))
+
+			loaded, err := LoadJournal(path, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Guids(JournalVerified)).To(Equal([]string{"guid-1"}))
+			Expect(loaded.Guids(JournalFlipped)).To(Equal([]string{"guid-2", "guid-3"}))
+		})
+
+		It("drops an update cut short by a crash", func() {
+			Expect(journal.Add(apps)).To(Succeed())
+			Expect(journal.Record(apps[0], JournalFlipped)).To(Succeed())
+			Expect(journal.Close()).To(Succeed())
+
+			file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = file.WriteString(`{"guid":"guid-1","na`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+
+			loaded, err := LoadJournal(path, ui.Diego)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Guids(JournalFlipped)).To(Equal([]string{"guid-1"}))
+		})
+
+		Context("when the journal cannot be written", func() {
+			BeforeEach(func() {
+				journal = NewJournal(filepath.Join(dir, "missing", "migration.json"), ui.Diego)
+			})
+
+			It("returns an error", func() {
+				Expect(journal.Record(apps[0], JournalVerified)).NotTo(Succeed())
+			})
+		})
+	})
+
+	Describe("LoadJournal", func() {
+		Context("when the journal records a different runtime", func() {
+			BeforeEach(func() {
+				Expect(NewJournal(path, ui.DEA).Add(apps)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := LoadJournal(path, ui.Diego)
+				Expect(err).To(Equal(JournalRuntimeMismatchError{Path: path, Runtime: ui.DEA}))
+			})
+		})
+
+		Context("when the journal is not valid", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(path, []byte("{"), 0600)).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := LoadJournal(path, ui.Diego)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		var appStatus *migratehelpersfakes.FakeAppStatusGetter
+
+		BeforeEach(func() {
+			appStatus = new(migratehelpersfakes.FakeAppStatusGetter)
+
+			Expect(journal.Add(apps)).To(Succeed())
+			Expect(journal.Record(apps[0], JournalVerified)).To(Succeed())
+			Expect(journal.Record(apps[1], JournalFlipped)).To(Succeed())
+		})
+
+		It("skips verified apps and re-checks flipped apps, in journal order", func() {
+			flippedApp := newApp("guid-2", "app-2")
+			flippedApp.Diego = true
+			appStatus.GetAppReturns(flippedApp, nil)
+
+			remaining, err := journal.Resume(models.Applications{apps[2]}, appStatus)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(appStatus.GetAppCallCount()).To(Equal(1))
+			Expect(appStatus.GetAppArgsForCall(0)).To(Equal("guid-2"))
+			Expect(remaining).To(Equal(models.Applications{flippedApp, apps[2]}))
+		})
+
+		It("puts apps the journal does not know last", func() {
+			newApp := newApp("guid-new", "app-new")
+
+			remaining, err := journal.Resume(models.Applications{newApp, apps[2], apps[1]}, appStatus)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(remaining).To(Equal(models.Applications{apps[1], apps[2], newApp}))
+		})
+
+		It("does not fetch flipped apps that are still listed", func() {
+			remaining, err := journal.Resume(apps, appStatus)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(appStatus.GetAppCallCount()).To(Equal(0))
+			Expect(remaining).To(Equal(models.Applications{apps[1], apps[2]}))
+		})
+
+		Context("when an app failed after it was flipped", func() {
+			BeforeEach(func() {
+				Expect(journal.Record(apps[2], JournalFailed)).To(Succeed())
+			})
+
+			It("re-checks it as well", func() {
+				appStatus.GetAppStub = func(guid string) (models.Application, error) {
+					app := newApp(guid, "")
+					app.Diego = true
+					return app, nil
+				}
+
+				remaining, err := journal.Resume(models.Applications{}, appStatus)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(appStatus.GetAppCallCount()).To(Equal(2))
+				Expect(appStatus.GetAppArgsForCall(1)).To(Equal("guid-3"))
+				Expect(remaining).To(HaveLen(2))
+				Expect(remaining[1].Guid).To(Equal("guid-3"))
+			})
+		})
+
+		Context("when a journaled app was deleted", func() {
+			BeforeEach(func() {
+				appStatus.GetAppReturns(models.Application{}, cloudcontroller.Error{ErrorCode: cloudcontroller.AppNotFoundErrorCode})
+			})
+
+			It("leaves it out", func() {
+				remaining, err := journal.Resume(models.Applications{apps[2]}, appStatus)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(remaining).To(Equal(models.Applications{apps[2]}))
+			})
+		})
+
+		Context("when fetching a flipped app fails", func() {
+			BeforeEach(func() {
+				appStatus.GetAppReturns(models.Application{}, errors.New("disaster"))
+			})
+
+			It("returns the error", func() {
+				_, err := journal.Resume(models.Applications{apps[2]}, appStatus)
+				Expect(err).To(MatchError("disaster"))
+			})
+		})
+	})
+})
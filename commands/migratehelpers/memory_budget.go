@@ -0,0 +1,77 @@
+package migratehelpers
+
+import "sync"
+
+// MemoryBudget limits the memory of the apps being migrated at once. Apps are
+// admitted in the order they ask so that large apps are not starved by small
+// ones, and an app larger than the whole budget is admitted on its own.
+type MemoryBudget struct {
+	limit int64
+
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	inFlight int64
+	next     int
+	served   int
+}
+
+// NewMemoryBudget returns a budget of limit megabytes. A limit of 0 admits
+// every app immediately.
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	b := &MemoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mutex)
+	return b
+}
+
+// Acquire blocks until the memory fits in the budget. It returns false without
+// acquiring anything when stop is closed first; no app is admitted after that.
+func (b *MemoryBudget) Acquire(memory int64, stop <-chan struct{}) bool {
+	if isClosed(stop) {
+		return false
+	}
+
+	if b.limit <= 0 {
+		return true
+	}
+
+	defer wakeOnStop(b.cond, stop)()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	ticket := b.next
+	b.next++
+
+	for ticket != b.served || (b.inFlight > 0 && b.inFlight+memory > b.limit) {
+		b.cond.Wait()
+		if isClosed(stop) {
+			return false
+		}
+	}
+
+	b.served++
+	b.inFlight += memory
+	b.cond.Broadcast()
+	return true
+}
+
+func (b *MemoryBudget) Release(memory int64) {
+	if b.limit <= 0 {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.inFlight -= memory
+	b.cond.Broadcast()
+}
+
+func isClosed(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package migratehelpers_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryBudget", func() {
+	var (
+		budget *MemoryBudget
+		stop   chan struct{}
+	)
+
+	BeforeEach(func() {
+		budget = NewMemoryBudget(1024)
+		stop = make(chan struct{})
+	})
+
+	acquire := func(memory int64) chan bool {
+		acquired := make(chan bool, 1)
+		go func() {
+			acquired <- budget.Acquire(memory, stop)
+		}()
+		return acquired
+	}
+
+	It("admits apps while they fit", func() {
+		Expect(budget.Acquire(512, stop)).To(BeTrue())
+		Expect(budget.Acquire(512, stop)).To(BeTrue())
+
+		acquired := acquire(1)
+		Consistently(acquired).ShouldNot(Receive())
+
+		budget.Release(512)
+		Eventually(acquired).Should(Receive(BeTrue()))
+	})
+
+	It("admits an app larger than the budget on its own", func() {
+		Expect(budget.Acquire(4096, stop)).To(BeTrue())
+
+		acquired := acquire(1)
+		Consistently(acquired).ShouldNot(Receive())
+
+		budget.Release(4096)
+		Eventually(acquired).Should(Receive(BeTrue()))
+	})
+
+	It("admits apps in the order they ask", func() {
+		Expect(budget.Acquire(512, stop)).To(BeTrue())
+
+		large := acquire(1024)
+		Consistently(large).ShouldNot(Receive())
+
+		small := acquire(256)
+		Consistently(small).ShouldNot(Receive())
+
+		budget.Release(512)
+		Eventually(large).Should(Receive(BeTrue()))
+		Consistently(small).ShouldNot(Receive())
+
+		budget.Release(1024)
+		Eventually(small).Should(Receive(BeTrue()))
+	})
+
+	It("gives up waiting when stopped", func() {
+		Expect(budget.Acquire(1024, stop)).To(BeTrue())
+
+		acquired := acquire(512)
+		Consistently(acquired).ShouldNot(Receive())
+
+		close(stop)
+		Eventually(acquired).Should(Receive(BeFalse()))
+		Expect(budget.Acquire(0, stop)).To(BeFalse())
+	})
+
+	Context("without a limit", func() {
+		BeforeEach(func() {
+			budget = NewMemoryBudget(0)
+		})
+
+		It("admits every app", func() {
+			Expect(budget.Acquire(1<<40, stop)).To(BeTrue())
+			Expect(budget.Acquire(1<<40, stop)).To(BeTrue())
+		})
+	})
+})
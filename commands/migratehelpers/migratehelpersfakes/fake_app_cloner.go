@@ -0,0 +1,605 @@
+// This file was generated by counterfeiter
+package migratehelpersfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type FakeAppCloner struct {
+	GetAppStub        func(string) (models.Application, error)
+	getAppMutex       sync.RWMutex
+	getAppArgsForCall []struct {
+		arg1 string
+	}
+	getAppReturns struct {
+		result1 models.Application
+		result2 error
+	}
+	GetAppInstancesStub        func(string) (models.AppInstances, error)
+	getAppInstancesMutex       sync.RWMutex
+	getAppInstancesArgsForCall []struct {
+		arg1 string
+	}
+	getAppInstancesReturns struct {
+		result1 models.AppInstances
+		result2 error
+	}
+	CreateAppStub        func(cloudcontroller.AppParams) (models.Application, error)
+	createAppMutex       sync.RWMutex
+	createAppArgsForCall []struct {
+		arg1 cloudcontroller.AppParams
+	}
+	createAppReturns struct {
+		result1 models.Application
+		result2 error
+	}
+	UpdateAppStub        func(string, cloudcontroller.AppParams) (models.Application, error)
+	updateAppMutex       sync.RWMutex
+	updateAppArgsForCall []struct {
+		arg1 string
+		arg2 cloudcontroller.AppParams
+	}
+	updateAppReturns struct {
+		result1 models.Application
+		result2 error
+	}
+	DeleteAppStub        func(string) error
+	deleteAppMutex       sync.RWMutex
+	deleteAppArgsForCall []struct {
+		arg1 string
+	}
+	deleteAppReturns struct {
+		result1 error
+	}
+	GetAppRoutesStub        func(string) (models.Routes, error)
+	getAppRoutesMutex       sync.RWMutex
+	getAppRoutesArgsForCall []struct {
+		arg1 string
+	}
+	getAppRoutesReturns struct {
+		result1 models.Routes
+		result2 error
+	}
+	MapRouteStub        func(string, string) error
+	mapRouteMutex       sync.RWMutex
+	mapRouteArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	mapRouteReturns struct {
+		result1 error
+	}
+	UnmapRouteStub        func(string, string) error
+	unmapRouteMutex       sync.RWMutex
+	unmapRouteArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	unmapRouteReturns struct {
+		result1 error
+	}
+	GetAppServiceBindingsStub        func(string) (models.ServiceBindings, error)
+	getAppServiceBindingsMutex       sync.RWMutex
+	getAppServiceBindingsArgsForCall []struct {
+		arg1 string
+	}
+	getAppServiceBindingsReturns struct {
+		result1 models.ServiceBindings
+		result2 error
+	}
+	BindServiceStub        func(string, string) error
+	bindServiceMutex       sync.RWMutex
+	bindServiceArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	bindServiceReturns struct {
+		result1 error
+	}
+	GetCurrentDropletStub        func(string) (models.Droplet, error)
+	getCurrentDropletMutex       sync.RWMutex
+	getCurrentDropletArgsForCall []struct {
+		arg1 string
+	}
+	getCurrentDropletReturns struct {
+		result1 models.Droplet
+		result2 error
+	}
+	CopyDropletStub        func(string, string) (models.Droplet, error)
+	copyDropletMutex       sync.RWMutex
+	copyDropletArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	copyDropletReturns struct {
+		result1 models.Droplet
+		result2 error
+	}
+	GetDropletStub        func(string) (models.Droplet, error)
+	getDropletMutex       sync.RWMutex
+	getDropletArgsForCall []struct {
+		arg1 string
+	}
+	getDropletReturns struct {
+		result1 models.Droplet
+		result2 error
+	}
+	SetCurrentDropletStub        func(string, string) error
+	setCurrentDropletMutex       sync.RWMutex
+	setCurrentDropletArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	setCurrentDropletReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeAppCloner) GetApp(arg1 string) (models.Application, error) {
+	fake.getAppMutex.Lock()
+	fake.getAppArgsForCall = append(fake.getAppArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppMutex.Unlock()
+	if fake.GetAppStub != nil {
+		return fake.GetAppStub(arg1)
+	} else {
+		return fake.getAppReturns.result1, fake.getAppReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) GetAppCallCount() int {
+	fake.getAppMutex.RLock()
+	defer fake.getAppMutex.RUnlock()
+	return len(fake.getAppArgsForCall)
+}
+
+func (fake *FakeAppCloner) GetAppArgsForCall(i int) string {
+	fake.getAppMutex.RLock()
+	defer fake.getAppMutex.RUnlock()
+	return fake.getAppArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) GetAppReturns(result1 models.Application, result2 error) {
+	fake.GetAppStub = nil
+	fake.getAppReturns = struct {
+		result1 models.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) GetAppInstances(arg1 string) (models.AppInstances, error) {
+	fake.getAppInstancesMutex.Lock()
+	fake.getAppInstancesArgsForCall = append(fake.getAppInstancesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppInstancesMutex.Unlock()
+	if fake.GetAppInstancesStub != nil {
+		return fake.GetAppInstancesStub(arg1)
+	} else {
+		return fake.getAppInstancesReturns.result1, fake.getAppInstancesReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) GetAppInstancesCallCount() int {
+	fake.getAppInstancesMutex.RLock()
+	defer fake.getAppInstancesMutex.RUnlock()
+	return len(fake.getAppInstancesArgsForCall)
+}
+
+func (fake *FakeAppCloner) GetAppInstancesArgsForCall(i int) string {
+	fake.getAppInstancesMutex.RLock()
+	defer fake.getAppInstancesMutex.RUnlock()
+	return fake.getAppInstancesArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) GetAppInstancesReturns(result1 models.AppInstances, result2 error) {
+	fake.GetAppInstancesStub = nil
+	fake.getAppInstancesReturns = struct {
+		result1 models.AppInstances
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) CreateApp(arg1 cloudcontroller.AppParams) (models.Application, error) {
+	fake.createAppMutex.Lock()
+	fake.createAppArgsForCall = append(fake.createAppArgsForCall, struct {
+		arg1 cloudcontroller.AppParams
+	}{arg1})
+	fake.createAppMutex.Unlock()
+	if fake.CreateAppStub != nil {
+		return fake.CreateAppStub(arg1)
+	} else {
+		return fake.createAppReturns.result1, fake.createAppReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) CreateAppCallCount() int {
+	fake.createAppMutex.RLock()
+	defer fake.createAppMutex.RUnlock()
+	return len(fake.createAppArgsForCall)
+}
+
+func (fake *FakeAppCloner) CreateAppArgsForCall(i int) cloudcontroller.AppParams {
+	fake.createAppMutex.RLock()
+	defer fake.createAppMutex.RUnlock()
+	return fake.createAppArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) CreateAppReturns(result1 models.Application, result2 error) {
+	fake.CreateAppStub = nil
+	fake.createAppReturns = struct {
+		result1 models.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) UpdateApp(arg1 string, arg2 cloudcontroller.AppParams) (models.Application, error) {
+	fake.updateAppMutex.Lock()
+	fake.updateAppArgsForCall = append(fake.updateAppArgsForCall, struct {
+		arg1 string
+		arg2 cloudcontroller.AppParams
+	}{arg1, arg2})
+	fake.updateAppMutex.Unlock()
+	if fake.UpdateAppStub != nil {
+		return fake.UpdateAppStub(arg1, arg2)
+	} else {
+		return fake.updateAppReturns.result1, fake.updateAppReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) UpdateAppCallCount() int {
+	fake.updateAppMutex.RLock()
+	defer fake.updateAppMutex.RUnlock()
+	return len(fake.updateAppArgsForCall)
+}
+
+func (fake *FakeAppCloner) UpdateAppArgsForCall(i int) (string, cloudcontroller.AppParams) {
+	fake.updateAppMutex.RLock()
+	defer fake.updateAppMutex.RUnlock()
+	return fake.updateAppArgsForCall[i].arg1, fake.updateAppArgsForCall[i].arg2
+}
+
+func (fake *FakeAppCloner) UpdateAppReturns(result1 models.Application, result2 error) {
+	fake.UpdateAppStub = nil
+	fake.updateAppReturns = struct {
+		result1 models.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) DeleteApp(arg1 string) error {
+	fake.deleteAppMutex.Lock()
+	fake.deleteAppArgsForCall = append(fake.deleteAppArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.deleteAppMutex.Unlock()
+	if fake.DeleteAppStub != nil {
+		return fake.DeleteAppStub(arg1)
+	} else {
+		return fake.deleteAppReturns.result1
+	}
+}
+
+func (fake *FakeAppCloner) DeleteAppCallCount() int {
+	fake.deleteAppMutex.RLock()
+	defer fake.deleteAppMutex.RUnlock()
+	return len(fake.deleteAppArgsForCall)
+}
+
+func (fake *FakeAppCloner) DeleteAppArgsForCall(i int) string {
+	fake.deleteAppMutex.RLock()
+	defer fake.deleteAppMutex.RUnlock()
+	return fake.deleteAppArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) DeleteAppReturns(result1 error) {
+	fake.DeleteAppStub = nil
+	fake.deleteAppReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppCloner) GetAppRoutes(arg1 string) (models.Routes, error) {
+	fake.getAppRoutesMutex.Lock()
+	fake.getAppRoutesArgsForCall = append(fake.getAppRoutesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppRoutesMutex.Unlock()
+	if fake.GetAppRoutesStub != nil {
+		return fake.GetAppRoutesStub(arg1)
+	} else {
+		return fake.getAppRoutesReturns.result1, fake.getAppRoutesReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) GetAppRoutesCallCount() int {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return len(fake.getAppRoutesArgsForCall)
+}
+
+func (fake *FakeAppCloner) GetAppRoutesArgsForCall(i int) string {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return fake.getAppRoutesArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) GetAppRoutesReturns(result1 models.Routes, result2 error) {
+	fake.GetAppRoutesStub = nil
+	fake.getAppRoutesReturns = struct {
+		result1 models.Routes
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) MapRoute(arg1 string, arg2 string) error {
+	fake.mapRouteMutex.Lock()
+	fake.mapRouteArgsForCall = append(fake.mapRouteArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.mapRouteMutex.Unlock()
+	if fake.MapRouteStub != nil {
+		return fake.MapRouteStub(arg1, arg2)
+	} else {
+		return fake.mapRouteReturns.result1
+	}
+}
+
+func (fake *FakeAppCloner) MapRouteCallCount() int {
+	fake.mapRouteMutex.RLock()
+	defer fake.mapRouteMutex.RUnlock()
+	return len(fake.mapRouteArgsForCall)
+}
+
+func (fake *FakeAppCloner) MapRouteArgsForCall(i int) (string, string) {
+	fake.mapRouteMutex.RLock()
+	defer fake.mapRouteMutex.RUnlock()
+	return fake.mapRouteArgsForCall[i].arg1, fake.mapRouteArgsForCall[i].arg2
+}
+
+func (fake *FakeAppCloner) MapRouteReturns(result1 error) {
+	fake.MapRouteStub = nil
+	fake.mapRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppCloner) UnmapRoute(arg1 string, arg2 string) error {
+	fake.unmapRouteMutex.Lock()
+	fake.unmapRouteArgsForCall = append(fake.unmapRouteArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.unmapRouteMutex.Unlock()
+	if fake.UnmapRouteStub != nil {
+		return fake.UnmapRouteStub(arg1, arg2)
+	} else {
+		return fake.unmapRouteReturns.result1
+	}
+}
+
+func (fake *FakeAppCloner) UnmapRouteCallCount() int {
+	fake.unmapRouteMutex.RLock()
+	defer fake.unmapRouteMutex.RUnlock()
+	return len(fake.unmapRouteArgsForCall)
+}
+
+func (fake *FakeAppCloner) UnmapRouteArgsForCall(i int) (string, string) {
+	fake.unmapRouteMutex.RLock()
+	defer fake.unmapRouteMutex.RUnlock()
+	return fake.unmapRouteArgsForCall[i].arg1, fake.unmapRouteArgsForCall[i].arg2
+}
+
+func (fake *FakeAppCloner) UnmapRouteReturns(result1 error) {
+	fake.UnmapRouteStub = nil
+	fake.unmapRouteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppCloner) GetAppServiceBindings(arg1 string) (models.ServiceBindings, error) {
+	fake.getAppServiceBindingsMutex.Lock()
+	fake.getAppServiceBindingsArgsForCall = append(fake.getAppServiceBindingsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppServiceBindingsMutex.Unlock()
+	if fake.GetAppServiceBindingsStub != nil {
+		return fake.GetAppServiceBindingsStub(arg1)
+	} else {
+		return fake.getAppServiceBindingsReturns.result1, fake.getAppServiceBindingsReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) GetAppServiceBindingsCallCount() int {
+	fake.getAppServiceBindingsMutex.RLock()
+	defer fake.getAppServiceBindingsMutex.RUnlock()
+	return len(fake.getAppServiceBindingsArgsForCall)
+}
+
+func (fake *FakeAppCloner) GetAppServiceBindingsArgsForCall(i int) string {
+	fake.getAppServiceBindingsMutex.RLock()
+	defer fake.getAppServiceBindingsMutex.RUnlock()
+	return fake.getAppServiceBindingsArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) GetAppServiceBindingsReturns(result1 models.ServiceBindings, result2 error) {
+	fake.GetAppServiceBindingsStub = nil
+	fake.getAppServiceBindingsReturns = struct {
+		result1 models.ServiceBindings
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) BindService(arg1 string, arg2 string) error {
+	fake.bindServiceMutex.Lock()
+	fake.bindServiceArgsForCall = append(fake.bindServiceArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.bindServiceMutex.Unlock()
+	if fake.BindServiceStub != nil {
+		return fake.BindServiceStub(arg1, arg2)
+	} else {
+		return fake.bindServiceReturns.result1
+	}
+}
+
+func (fake *FakeAppCloner) BindServiceCallCount() int {
+	fake.bindServiceMutex.RLock()
+	defer fake.bindServiceMutex.RUnlock()
+	return len(fake.bindServiceArgsForCall)
+}
+
+func (fake *FakeAppCloner) BindServiceArgsForCall(i int) (string, string) {
+	fake.bindServiceMutex.RLock()
+	defer fake.bindServiceMutex.RUnlock()
+	return fake.bindServiceArgsForCall[i].arg1, fake.bindServiceArgsForCall[i].arg2
+}
+
+func (fake *FakeAppCloner) BindServiceReturns(result1 error) {
+	fake.BindServiceStub = nil
+	fake.bindServiceReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAppCloner) GetCurrentDroplet(arg1 string) (models.Droplet, error) {
+	fake.getCurrentDropletMutex.Lock()
+	fake.getCurrentDropletArgsForCall = append(fake.getCurrentDropletArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getCurrentDropletMutex.Unlock()
+	if fake.GetCurrentDropletStub != nil {
+		return fake.GetCurrentDropletStub(arg1)
+	} else {
+		return fake.getCurrentDropletReturns.result1, fake.getCurrentDropletReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) GetCurrentDropletCallCount() int {
+	fake.getCurrentDropletMutex.RLock()
+	defer fake.getCurrentDropletMutex.RUnlock()
+	return len(fake.getCurrentDropletArgsForCall)
+}
+
+func (fake *FakeAppCloner) GetCurrentDropletArgsForCall(i int) string {
+	fake.getCurrentDropletMutex.RLock()
+	defer fake.getCurrentDropletMutex.RUnlock()
+	return fake.getCurrentDropletArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) GetCurrentDropletReturns(result1 models.Droplet, result2 error) {
+	fake.GetCurrentDropletStub = nil
+	fake.getCurrentDropletReturns = struct {
+		result1 models.Droplet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) CopyDroplet(arg1 string, arg2 string) (models.Droplet, error) {
+	fake.copyDropletMutex.Lock()
+	fake.copyDropletArgsForCall = append(fake.copyDropletArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.copyDropletMutex.Unlock()
+	if fake.CopyDropletStub != nil {
+		return fake.CopyDropletStub(arg1, arg2)
+	} else {
+		return fake.copyDropletReturns.result1, fake.copyDropletReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) CopyDropletCallCount() int {
+	fake.copyDropletMutex.RLock()
+	defer fake.copyDropletMutex.RUnlock()
+	return len(fake.copyDropletArgsForCall)
+}
+
+func (fake *FakeAppCloner) CopyDropletArgsForCall(i int) (string, string) {
+	fake.copyDropletMutex.RLock()
+	defer fake.copyDropletMutex.RUnlock()
+	return fake.copyDropletArgsForCall[i].arg1, fake.copyDropletArgsForCall[i].arg2
+}
+
+func (fake *FakeAppCloner) CopyDropletReturns(result1 models.Droplet, result2 error) {
+	fake.CopyDropletStub = nil
+	fake.copyDropletReturns = struct {
+		result1 models.Droplet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) GetDroplet(arg1 string) (models.Droplet, error) {
+	fake.getDropletMutex.Lock()
+	fake.getDropletArgsForCall = append(fake.getDropletArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getDropletMutex.Unlock()
+	if fake.GetDropletStub != nil {
+		return fake.GetDropletStub(arg1)
+	} else {
+		return fake.getDropletReturns.result1, fake.getDropletReturns.result2
+	}
+}
+
+func (fake *FakeAppCloner) GetDropletCallCount() int {
+	fake.getDropletMutex.RLock()
+	defer fake.getDropletMutex.RUnlock()
+	return len(fake.getDropletArgsForCall)
+}
+
+func (fake *FakeAppCloner) GetDropletArgsForCall(i int) string {
+	fake.getDropletMutex.RLock()
+	defer fake.getDropletMutex.RUnlock()
+	return fake.getDropletArgsForCall[i].arg1
+}
+
+func (fake *FakeAppCloner) GetDropletReturns(result1 models.Droplet, result2 error) {
+	fake.GetDropletStub = nil
+	fake.getDropletReturns = struct {
+		result1 models.Droplet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppCloner) SetCurrentDroplet(arg1 string, arg2 string) error {
+	fake.setCurrentDropletMutex.Lock()
+	fake.setCurrentDropletArgsForCall = append(fake.setCurrentDropletArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.setCurrentDropletMutex.Unlock()
+	if fake.SetCurrentDropletStub != nil {
+		return fake.SetCurrentDropletStub(arg1, arg2)
+	} else {
+		return fake.setCurrentDropletReturns.result1
+	}
+}
+
+func (fake *FakeAppCloner) SetCurrentDropletCallCount() int {
+	fake.setCurrentDropletMutex.RLock()
+	defer fake.setCurrentDropletMutex.RUnlock()
+	return len(fake.setCurrentDropletArgsForCall)
+}
+
+func (fake *FakeAppCloner) SetCurrentDropletArgsForCall(i int) (string, string) {
+	fake.setCurrentDropletMutex.RLock()
+	defer fake.setCurrentDropletMutex.RUnlock()
+	return fake.setCurrentDropletArgsForCall[i].arg1, fake.setCurrentDropletArgsForCall[i].arg2
+}
+
+func (fake *FakeAppCloner) SetCurrentDropletReturns(result1 error) {
+	fake.SetCurrentDropletStub = nil
+	fake.setCurrentDropletReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ migratehelpers.AppCloner = new(FakeAppCloner)
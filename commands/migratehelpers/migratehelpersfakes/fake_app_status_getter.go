@@ -0,0 +1,98 @@
+// This file was generated by counterfeiter
+package migratehelpersfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type FakeAppStatusGetter struct {
+	GetAppStub        func(string) (models.Application, error)
+	getAppMutex       sync.RWMutex
+	getAppArgsForCall []struct {
+		arg1 string
+	}
+	getAppReturns struct {
+		result1 models.Application
+		result2 error
+	}
+	GetAppInstancesStub        func(string) (models.AppInstances, error)
+	getAppInstancesMutex       sync.RWMutex
+	getAppInstancesArgsForCall []struct {
+		arg1 string
+	}
+	getAppInstancesReturns struct {
+		result1 models.AppInstances
+		result2 error
+	}
+}
+
+func (fake *FakeAppStatusGetter) GetApp(arg1 string) (models.Application, error) {
+	fake.getAppMutex.Lock()
+	fake.getAppArgsForCall = append(fake.getAppArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppMutex.Unlock()
+	if fake.GetAppStub != nil {
+		return fake.GetAppStub(arg1)
+	} else {
+		return fake.getAppReturns.result1, fake.getAppReturns.result2
+	}
+}
+
+func (fake *FakeAppStatusGetter) GetAppCallCount() int {
+	fake.getAppMutex.RLock()
+	defer fake.getAppMutex.RUnlock()
+	return len(fake.getAppArgsForCall)
+}
+
+func (fake *FakeAppStatusGetter) GetAppArgsForCall(i int) string {
+	fake.getAppMutex.RLock()
+	defer fake.getAppMutex.RUnlock()
+	return fake.getAppArgsForCall[i].arg1
+}
+
+func (fake *FakeAppStatusGetter) GetAppReturns(result1 models.Application, result2 error) {
+	fake.GetAppStub = nil
+	fake.getAppReturns = struct {
+		result1 models.Application
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppStatusGetter) GetAppInstances(arg1 string) (models.AppInstances, error) {
+	fake.getAppInstancesMutex.Lock()
+	fake.getAppInstancesArgsForCall = append(fake.getAppInstancesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppInstancesMutex.Unlock()
+	if fake.GetAppInstancesStub != nil {
+		return fake.GetAppInstancesStub(arg1)
+	} else {
+		return fake.getAppInstancesReturns.result1, fake.getAppInstancesReturns.result2
+	}
+}
+
+func (fake *FakeAppStatusGetter) GetAppInstancesCallCount() int {
+	fake.getAppInstancesMutex.RLock()
+	defer fake.getAppInstancesMutex.RUnlock()
+	return len(fake.getAppInstancesArgsForCall)
+}
+
+func (fake *FakeAppStatusGetter) GetAppInstancesArgsForCall(i int) string {
+	fake.getAppInstancesMutex.RLock()
+	defer fake.getAppInstancesMutex.RUnlock()
+	return fake.getAppInstancesArgsForCall[i].arg1
+}
+
+func (fake *FakeAppStatusGetter) GetAppInstancesReturns(result1 models.AppInstances, result2 error) {
+	fake.GetAppInstancesStub = nil
+	fake.getAppInstancesReturns = struct {
+		result1 models.AppInstances
+		result2 error
+	}{result1, result2}
+}
+
+var _ migratehelpers.AppStatusGetter = new(FakeAppStatusGetter)
@@ -0,0 +1,56 @@
+// This file was generated by counterfeiter
+package migratehelpersfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type FakeRouteGetter struct {
+	GetAppRoutesStub        func(string) (models.Routes, error)
+	getAppRoutesMutex       sync.RWMutex
+	getAppRoutesArgsForCall []struct {
+		arg1 string
+	}
+	getAppRoutesReturns struct {
+		result1 models.Routes
+		result2 error
+	}
+}
+
+func (fake *FakeRouteGetter) GetAppRoutes(arg1 string) (models.Routes, error) {
+	fake.getAppRoutesMutex.Lock()
+	fake.getAppRoutesArgsForCall = append(fake.getAppRoutesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppRoutesMutex.Unlock()
+	if fake.GetAppRoutesStub != nil {
+		return fake.GetAppRoutesStub(arg1)
+	} else {
+		return fake.getAppRoutesReturns.result1, fake.getAppRoutesReturns.result2
+	}
+}
+
+func (fake *FakeRouteGetter) GetAppRoutesCallCount() int {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return len(fake.getAppRoutesArgsForCall)
+}
+
+func (fake *FakeRouteGetter) GetAppRoutesArgsForCall(i int) string {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return fake.getAppRoutesArgsForCall[i].arg1
+}
+
+func (fake *FakeRouteGetter) GetAppRoutesReturns(result1 models.Routes, result2 error) {
+	fake.GetAppRoutesStub = nil
+	fake.getAppRoutesReturns = struct {
+		result1 models.Routes
+		result2 error
+	}{result1, result2}
+}
+
+var _ migratehelpers.RouteGetter = new(FakeRouteGetter)
@@ -0,0 +1,88 @@
+package migratehelpers
+
+import (
+	"sort"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type OrderBy string
+
+const (
+	OrderByName           OrderBy = "name"
+	OrderByMemory         OrderBy = "memory"
+	OrderByInstances      OrderBy = "instances"
+	OrderBySpace          OrderBy = "space"
+	OrderByPackageUpdated OrderBy = "package-updated"
+)
+
+// Ordering decides the order apps are migrated in. Apps that compare equal
+// keep the order they were listed in.
+type Ordering struct {
+	By   OrderBy
+	Desc bool
+}
+
+// Sort orders the apps in place. Memory is the memory restarting an app takes
+// up, so stopped apps sort as the smallest.
+func (o Ordering) Sort(apps models.Applications, spaceMap map[string]models.Space) {
+	less := o.less(spaceMap)
+	if less == nil {
+		return
+	}
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		if o.Desc {
+			return less(apps[j], apps[i])
+		}
+		return less(apps[i], apps[j])
+	})
+}
+
+func (o Ordering) less(spaceMap map[string]models.Space) func(a, b models.Application) bool {
+	switch o.By {
+	case OrderByName:
+		return func(a, b models.Application) bool {
+			return a.Name < b.Name
+		}
+	case OrderByMemory:
+		return func(a, b models.Application) bool {
+			return a.StartedMemory() < b.StartedMemory()
+		}
+	case OrderByInstances:
+		return func(a, b models.Application) bool {
+			return a.Instances < b.Instances
+		}
+	case OrderBySpace:
+		return func(a, b models.Application) bool {
+			return locationLess(appPrinter(a, spaceMap), appPrinter(b, spaceMap))
+		}
+	case OrderByPackageUpdated:
+		return func(a, b models.Application) bool {
+			if a.PackageUpdatedAt == nil || b.PackageUpdatedAt == nil {
+				return a.PackageUpdatedAt == nil && b.PackageUpdatedAt != nil
+			}
+			return a.PackageUpdatedAt.Before(*b.PackageUpdatedAt)
+		}
+	default:
+		return nil
+	}
+}
+
+func appPrinter(app models.Application, spaceMap map[string]models.Space) *displayhelpers.AppPrinter {
+	return &displayhelpers.AppPrinter{
+		App:    app,
+		Spaces: spaceMap,
+	}
+}
+
+func locationLess(a, b *displayhelpers.AppPrinter) bool {
+	if a.Organization() != b.Organization() {
+		return a.Organization() < b.Organization()
+	}
+	if a.Space() != b.Space() {
+		return a.Space() < b.Space()
+	}
+	return a.Name() < b.Name()
+}
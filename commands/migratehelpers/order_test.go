@@ -0,0 +1,77 @@
+package migratehelpers_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ordering", func() {
+	var (
+		apps     models.Applications
+		spaceMap map[string]models.Space
+	)
+
+	names := func() []string {
+		var names []string
+		for _, app := range apps {
+			names = append(names, app.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		older := time.Date(2016, 3, 16, 0, 0, 0, 0, time.UTC)
+		newer := older.Add(time.Hour)
+
+		apps = models.Applications{
+			{ApplicationEntity: models.ApplicationEntity{Name: "c", State: models.Started, Memory: 256, Instances: 4, SpaceGuid: "space-1", PackageUpdatedAt: &newer}},
+			{ApplicationEntity: models.ApplicationEntity{Name: "a", State: models.Started, Memory: 512, Instances: 1, SpaceGuid: "space-2", PackageUpdatedAt: &older}},
+			{ApplicationEntity: models.ApplicationEntity{Name: "b", State: models.Stopped, Memory: 2048, Instances: 2, SpaceGuid: "space-1"}},
+		}
+		spaceMap = map[string]models.Space{
+			"space-1": {SpaceEntity: models.SpaceEntity{Name: "zeta", Organization: models.Organization{OrganizationEntity: models.OrganizationEntity{Name: "org"}}}},
+			"space-2": {SpaceEntity: models.SpaceEntity{Name: "alpha", Organization: models.Organization{OrganizationEntity: models.OrganizationEntity{Name: "org"}}}},
+		}
+	})
+
+	It("orders by name", func() {
+		Ordering{By: OrderByName}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("orders by the memory restarting each app takes up", func() {
+		Ordering{By: OrderByMemory}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"b", "a", "c"}))
+	})
+
+	It("orders by instances", func() {
+		Ordering{By: OrderByInstances, Desc: true}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"c", "b", "a"}))
+	})
+
+	It("orders by org, space and name", func() {
+		Ordering{By: OrderBySpace}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("orders by package update, apps without a package first", func() {
+		Ordering{By: OrderByPackageUpdated}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"b", "a", "c"}))
+	})
+
+	It("keeps the order of apps that compare equal", func() {
+		apps[1].Instances = 4
+		Ordering{By: OrderByInstances, Desc: true}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"c", "a", "b"}))
+	})
+
+	It("leaves the apps alone without an order", func() {
+		Ordering{}.Sort(apps, spaceMap)
+		Expect(names()).To(Equal([]string{"c", "a", "b"}))
+	})
+})
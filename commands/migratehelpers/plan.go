@@ -0,0 +1,231 @@
+package migratehelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+	"gopkg.in/yaml.v2"
+)
+
+// Plan lists the apps a migration should process. Entries with an explicit
+// order are migrated first, lowest order first; the rest follow in the order
+// they are listed. Each app may only appear once.
+type Plan struct {
+	Apps []PlanEntry `json:"apps" yaml:"apps"`
+}
+
+// PlanEntry identifies an app either by guid or by org, space and name. The
+// runtime and timeout are optional and default to the command line values.
+// An order of 0 means the entry is unordered.
+type PlanEntry struct {
+	Guid         string `json:"guid,omitempty" yaml:"guid,omitempty"`
+	Organization string `json:"org,omitempty" yaml:"org,omitempty"`
+	Space        string `json:"space,omitempty" yaml:"space,omitempty"`
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+	Runtime      string `json:"runtime,omitempty" yaml:"runtime,omitempty"`
+	Order        int    `json:"order,omitempty" yaml:"order,omitempty"`
+	Timeout      string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+func (e PlanEntry) String() string {
+	if e.Guid != "" {
+		return e.Guid
+	}
+	return fmt.Sprintf("%s/%s/%s", e.Organization, e.Space, e.Name)
+}
+
+type InvalidPlanEntryError struct {
+	Entry  PlanEntry
+	Reason string
+}
+
+func (e InvalidPlanEntryError) Error() string {
+	return fmt.Sprintf("Invalid plan entry %s: %s", e.Entry, e.Reason)
+}
+
+// Target is the runtime and timeouts a single app is migrated with.
+type Target struct {
+	Runtime  ui.Runtime
+	Timeouts Timeouts
+}
+
+func LoadPlan(path string) (Plan, error) {
+	var plan Plan
+
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	// YAML is a superset of JSON, so this reads plans in either format.
+	err = yaml.Unmarshal(body, &plan)
+	if err != nil {
+		return Plan{}, fmt.Errorf("Invalid plan %s: %s", path, err)
+	}
+
+	for _, entry := range plan.Apps {
+		if err := entry.validate(); err != nil {
+			return Plan{}, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Save writes the plan as JSON when path ends in .json and as YAML otherwise.
+func (p Plan) Save(path string) error {
+	var (
+		body []byte
+		err  error
+	)
+
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		body, err = json.MarshalIndent(p, "", "  ")
+	} else {
+		body, err = yaml.Marshal(p)
+	}
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomically(path, body)
+}
+
+func (e PlanEntry) validate() error {
+	if e.Guid == "" && (e.Organization == "" || e.Space == "" || e.Name == "") {
+		return InvalidPlanEntryError{Entry: e, Reason: "either guid or org, space and name are required"}
+	}
+
+	if e.Runtime != "" {
+		if _, err := ui.ParseRuntime(e.Runtime); err != nil {
+			return InvalidPlanEntryError{Entry: e, Reason: err.Error()}
+		}
+	}
+
+	if e.Timeout != "" {
+		if _, err := time.ParseDuration(e.Timeout); err != nil {
+			return InvalidPlanEntryError{Entry: e, Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+type planEntriesByOrder []PlanEntry
+
+func (p planEntriesByOrder) Len() int      { return len(p) }
+func (p planEntriesByOrder) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p planEntriesByOrder) Less(i, j int) bool {
+	if (p[i].Order == 0) != (p[j].Order == 0) {
+		return p[j].Order == 0
+	}
+	return p[i].Order < p[j].Order
+}
+
+// Resolve looks up the apps listed in the plan, in plan order, along with the
+// runtime and timeouts each of them is to be migrated with. It fails when two
+// entries resolve to the same app.
+func (p Plan) Resolve(
+	appsParser thingdoer.ApplicationsParser,
+	paginatedRequester thingdoer.PaginatedRequester,
+	appStatus AppStatusGetter,
+	spaces models.Spaces,
+	defaults Target,
+) (models.Applications, map[string]Target, error) {
+	entries := make(planEntriesByOrder, len(p.Apps))
+	copy(entries, p.Apps)
+	sort.Stable(entries)
+
+	var apps models.Applications
+	targets := map[string]Target{}
+	resolved := map[string]PlanEntry{}
+
+	for _, entry := range entries {
+		app, err := entry.resolve(appsParser, paginatedRequester, appStatus, spaces)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if previous, ok := resolved[app.Guid]; ok {
+			return nil, nil, InvalidPlanEntryError{
+				Entry:  entry,
+				Reason: fmt.Sprintf("app is already listed as %s", previous),
+			}
+		}
+		resolved[app.Guid] = entry
+
+		target := defaults
+		if entry.Runtime != "" {
+			target.Runtime, err = ui.ParseRuntime(entry.Runtime)
+			if err != nil {
+				return nil, nil, InvalidPlanEntryError{Entry: entry, Reason: err.Error()}
+			}
+		}
+		if entry.Timeout != "" {
+			target.Timeouts.Startup, err = time.ParseDuration(entry.Timeout)
+			if err != nil {
+				return nil, nil, InvalidPlanEntryError{Entry: entry, Reason: err.Error()}
+			}
+		}
+
+		apps = append(apps, app)
+		targets[app.Guid] = target
+	}
+
+	return apps, targets, nil
+}
+
+func (e PlanEntry) resolve(
+	appsParser thingdoer.ApplicationsParser,
+	paginatedRequester thingdoer.PaginatedRequester,
+	appStatus AppStatusGetter,
+	spaces models.Spaces,
+) (models.Application, error) {
+	if e.Guid != "" {
+		return appStatus.GetApp(e.Guid)
+	}
+
+	var spaceGuid string
+	for _, space := range spaces {
+		if space.Name == e.Space && space.Organization.Name == e.Organization {
+			spaceGuid = space.Guid
+			break
+		}
+	}
+	if spaceGuid == "" {
+		return models.Application{}, InvalidPlanEntryError{Entry: e, Reason: "space not found"}
+	}
+
+	apps, err := thingdoer.Apps(
+		appsParser,
+		paginatedRequester,
+		api.Filters{
+			api.EqualFilter{
+				Name:  "name",
+				Value: e.Name,
+			},
+			api.EqualFilter{
+				Name:  "space_guid",
+				Value: spaceGuid,
+			},
+		},
+	)
+	if err != nil {
+		return models.Application{}, err
+	}
+
+	if len(apps) != 1 {
+		return models.Application{}, InvalidPlanEntryError{Entry: e, Reason: "app not found"}
+	}
+
+	return apps[0], nil
+}
@@ -0,0 +1,305 @@
+package migratehelpers_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer/thingdoerfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plan", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "plan")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writePlan := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(body), 0600)).To(Succeed())
+		return path
+	}
+
+	Describe("LoadPlan", func() {
+		It("reads YAML plans", func() {
+			path := writePlan("plan.yml", `---
+apps:
+- guid: some-app-guid
+  runtime: dea
+  timeout: 10m
+- org: some-org
+  space: some-space
+  name: some-app
+  order: 2
+`)
+
+			plan, err := LoadPlan(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Apps).To(Equal([]PlanEntry{
+				{Guid: "some-app-guid", Runtime: "dea", Timeout: "10m"},
+				{Organization: "some-org", Space: "some-space", Name: "some-app", Order: 2},
+			}))
+		})
+
+		It("reads JSON plans", func() {
+			path := writePlan("plan.json", `{"apps": [{"guid": "some-app-guid", "runtime": "diego"}]}`)
+
+			plan, err := LoadPlan(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan.Apps).To(Equal([]PlanEntry{
+				{Guid: "some-app-guid", Runtime: "diego"},
+			}))
+		})
+
+		Context("when an entry does not identify an app", func() {
+			It("returns an error", func() {
+				path := writePlan("plan.yml", "apps:\n- org: some-org\n  name: some-app\n")
+
+				_, err := LoadPlan(path)
+				Expect(err).To(BeAssignableToTypeOf(InvalidPlanEntryError{}))
+			})
+		})
+
+		Context("when an entry has an unknown runtime", func() {
+			It("returns an error", func() {
+				path := writePlan("plan.yml", "apps:\n- guid: some-app-guid\n  runtime: banana\n")
+
+				_, err := LoadPlan(path)
+				Expect(err).To(BeAssignableToTypeOf(InvalidPlanEntryError{}))
+			})
+		})
+
+		Context("when an entry has an invalid timeout", func() {
+			It("returns an error", func() {
+				path := writePlan("plan.yml", "apps:\n- guid: some-app-guid\n  timeout: forever\n")
+
+				_, err := LoadPlan(path)
+				Expect(err).To(BeAssignableToTypeOf(InvalidPlanEntryError{}))
+			})
+		})
+	})
+
+	Describe("Save", func() {
+		var plan Plan
+
+		BeforeEach(func() {
+			plan = Plan{
+				Apps: []PlanEntry{
+					{Guid: "some-app-guid", Organization: "some-org", Space: "some-space", Name: "some-app", Runtime: "diego"},
+				},
+			}
+		})
+
+		It("writes YAML that can be loaded again", func() {
+			path := filepath.Join(dir, "plan.yml")
+			Expect(plan.Save(path)).To(Succeed())
+
+			body, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(ContainSubstring("guid: some-app-guid"))
+
+			loaded, err := LoadPlan(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(plan))
+		})
+
+		It("writes JSON when the file has a .json extension", func() {
+			path := filepath.Join(dir, "plan.json")
+			Expect(plan.Save(path)).To(Succeed())
+
+			body, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(ContainSubstring(`"guid": "some-app-guid"`))
+
+			loaded, err := LoadPlan(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(plan))
+		})
+	})
+
+	Describe("Resolve", func() {
+		var (
+			plan                   Plan
+			fakePaginatedRequester *thingdoerfakes.FakePaginatedRequester
+			fakeApplicationsParser *thingdoerfakes.FakeApplicationsParser
+			appStatus              *migratehelpersfakes.FakeAppStatusGetter
+			spaces                 models.Spaces
+			defaults               Target
+
+			apps    models.Applications
+			targets map[string]Target
+			err     error
+		)
+
+		BeforeEach(func() {
+			fakePaginatedRequester = new(thingdoerfakes.FakePaginatedRequester)
+			fakePaginatedRequester.DoReturns([][]byte{[]byte("some-json")}, nil)
+
+			fakeApplicationsParser = new(thingdoerfakes.FakeApplicationsParser)
+			fakeApplicationsParser.ParseReturns(models.Applications{
+				{ApplicationMetadata: models.ApplicationMetadata{Guid: "named-app-guid"}},
+			}, nil)
+
+			appStatus = new(migratehelpersfakes.FakeAppStatusGetter)
+			appStatus.GetAppStub = func(guid string) (models.Application, error) {
+				return models.Application{ApplicationMetadata: models.ApplicationMetadata{Guid: guid}}, nil
+			}
+
+			spaces = models.Spaces{
+				{
+					SpaceEntity: models.SpaceEntity{
+						Name: "some-space",
+						Organization: models.Organization{
+							OrganizationEntity: models.OrganizationEntity{Name: "some-org"},
+						},
+					},
+					SpaceMetadata: models.SpaceMetadata{Guid: "some-space-guid"},
+				},
+			}
+
+			defaults = Target{
+				Runtime: ui.Diego,
+				Timeouts: Timeouts{
+					Staging: time.Minute,
+					Startup: time.Minute,
+				},
+			}
+
+			plan = Plan{
+				Apps: []PlanEntry{
+					{Guid: "guid-app-guid", Runtime: "dea", Timeout: "10m", Order: 2},
+					{Organization: "some-org", Space: "some-space", Name: "some-app", Order: 1},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			apps, targets, err = plan.Resolve(fakeApplicationsParser, fakePaginatedRequester, appStatus, spaces, defaults)
+		})
+
+		It("returns the apps in order", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apps).To(HaveLen(2))
+			Expect(apps[0].Guid).To(Equal("named-app-guid"))
+			Expect(apps[1].Guid).To(Equal("guid-app-guid"))
+		})
+
+		Context("when some entries have no order", func() {
+			BeforeEach(func() {
+				plan.Apps = append([]PlanEntry{{Guid: "unordered-app-guid"}}, plan.Apps...)
+			})
+
+			It("returns them after the ordered apps", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(apps).To(HaveLen(3))
+				Expect(apps[0].Guid).To(Equal("named-app-guid"))
+				Expect(apps[1].Guid).To(Equal("guid-app-guid"))
+				Expect(apps[2].Guid).To(Equal("unordered-app-guid"))
+			})
+		})
+
+		Context("when the same guid is listed twice", func() {
+			BeforeEach(func() {
+				plan.Apps = append(plan.Apps, PlanEntry{Guid: "guid-app-guid"})
+			})
+
+			It("returns an error naming the entry", func() {
+				Expect(err).To(MatchError("Invalid plan entry guid-app-guid: app is already listed as guid-app-guid"))
+			})
+		})
+
+		Context("when an app is listed by guid and by name", func() {
+			BeforeEach(func() {
+				plan.Apps = append(plan.Apps, PlanEntry{Guid: "named-app-guid"})
+			})
+
+			It("returns an error naming the entry", func() {
+				Expect(err).To(MatchError("Invalid plan entry named-app-guid: app is already listed as some-org/some-space/some-app"))
+			})
+		})
+
+		Context("when an entry has an invalid timeout", func() {
+			BeforeEach(func() {
+				plan.Apps[0].Timeout = "ten minutes"
+			})
+
+			It("returns an error naming the entry", func() {
+				Expect(err).To(BeAssignableToTypeOf(InvalidPlanEntryError{}))
+				Expect(err.Error()).To(HavePrefix("Invalid plan entry guid-app-guid: "))
+			})
+		})
+
+		It("looks up apps by name in their space", func() {
+			Expect(fakePaginatedRequester.DoCallCount()).To(Equal(1))
+			filter, _ := fakePaginatedRequester.DoArgsForCall(0)
+			Expect(filter).To(Equal(api.Filters{
+				api.EqualFilter{Name: "name", Value: "some-app"},
+				api.EqualFilter{Name: "space_guid", Value: "some-space-guid"},
+			}))
+		})
+
+		It("looks up apps by guid", func() {
+			Expect(appStatus.GetAppCallCount()).To(Equal(1))
+			Expect(appStatus.GetAppArgsForCall(0)).To(Equal("guid-app-guid"))
+		})
+
+		It("returns the target of every app", func() {
+			Expect(targets["named-app-guid"]).To(Equal(defaults))
+			Expect(targets["guid-app-guid"]).To(Equal(Target{
+				Runtime: ui.DEA,
+				Timeouts: Timeouts{
+					Staging: time.Minute,
+					Startup: 10 * time.Minute,
+				},
+			}))
+		})
+
+		Context("when the space does not exist", func() {
+			BeforeEach(func() {
+				plan.Apps[1].Space = "some-other-space"
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(BeAssignableToTypeOf(InvalidPlanEntryError{}))
+			})
+		})
+
+		Context("when the app does not exist", func() {
+			BeforeEach(func() {
+				fakeApplicationsParser.ParseReturns(models.Applications{}, nil)
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(BeAssignableToTypeOf(InvalidPlanEntryError{}))
+			})
+		})
+
+		Context("when looking up an app by guid fails", func() {
+			BeforeEach(func() {
+				appStatus.GetAppStub = nil
+				appStatus.GetAppReturns(models.Application{}, errors.New("disaster"))
+			})
+
+			It("returns the error", func() {
+				Expect(err).To(MatchError("disaster"))
+			})
+		})
+	})
+})
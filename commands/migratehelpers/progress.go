@@ -0,0 +1,43 @@
+package migratehelpers
+
+import (
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+// progressAppStatus reports the staging and instance status of an app to the
+// progress view as waitForApp polls it.
+type progressAppStatus struct {
+	AppStatusGetter
+	app                ui.ApplicationPrinter
+	migrateAppsCommand *ui.MigrateAppsCommand
+	expectedRunning    int
+	phase              *string
+}
+
+func (s progressAppStatus) GetApp(appGuid string) (models.Application, error) {
+	app, err := s.AppStatusGetter.GetApp(appGuid)
+	if err == nil && app.PackageState == models.PackagePending {
+		s.setPhase("staging")
+	}
+	return app, err
+}
+
+func (s progressAppStatus) GetAppInstances(appGuid string) (models.AppInstances, error) {
+	instances, err := s.AppStatusGetter.GetAppInstances(appGuid)
+	if err == nil {
+		s.setPhase("starting instances")
+		s.migrateAppsCommand.InstancesEach(s.app, instances.Running(), s.expectedRunning)
+	}
+	return instances, err
+}
+
+// setPhase only reports changes, as line-oriented progress prints every phase.
+func (s progressAppStatus) setPhase(phase string) {
+	if *s.phase == phase {
+		return
+	}
+
+	*s.phase = phase
+	s.migrateAppsCommand.PhaseEach(s.app, phase)
+}
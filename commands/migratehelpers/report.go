@@ -0,0 +1,253 @@
+package migratehelpers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type ReportFormat string
+
+const (
+	ReportJSON  ReportFormat = "json"
+	ReportCSV   ReportFormat = "csv"
+	ReportJUnit ReportFormat = "junit"
+)
+
+var outcomeNames = map[int]string{
+	Success:        "success",
+	Warning:        "warning",
+	Err:            "error",
+	StagingFailed:  "staging_failed",
+	Crashed:        "crashed",
+	TimedOut:       "timed_out",
+	RolledBack:     "rolled_back",
+	RollbackFailed: "rollback_failed",
+	Degraded:       "degraded",
+	NotAttempted:   "not_attempted",
+}
+
+func OutcomeName(outcome int) string {
+	return outcomeNames[outcome]
+}
+
+// Report records the result of migrating each app. Entries are sorted by org,
+// space and name so that reports of different runs can be diffed.
+type Report struct {
+	Apps []ReportEntry `json:"apps"`
+}
+
+type ReportEntry struct {
+	Guid            string  `json:"guid"`
+	Name            string  `json:"name"`
+	Organization    string  `json:"org"`
+	Space           string  `json:"space"`
+	PreviousRuntime string  `json:"previous_runtime"`
+	Runtime         string  `json:"runtime"`
+	Outcome         string  `json:"outcome"`
+	Error           string  `json:"error,omitempty"`
+	Attempts        int     `json:"attempts"`
+	Duration        float64 `json:"duration_seconds"`
+}
+
+// report lists every selected app. Apps that were never attempted, because
+// the migration was interrupted, the canaries were not confirmed or the app
+// was held back with its route group, are reported as not attempted.
+func (cmd *MigrateApps) report(apps models.Applications, spaceMap map[string]models.Space, results []migrationResult) Report {
+	var report Report
+
+	attempted := map[string]bool{}
+	for _, result := range results {
+		attempted[result.AppPrinter.App.Guid] = true
+
+		entry := ReportEntry{
+			Guid:            result.AppPrinter.App.Guid,
+			Name:            result.AppPrinter.Name(),
+			Organization:    result.AppPrinter.Organization(),
+			Space:           result.AppPrinter.Space(),
+			PreviousRuntime: strings.ToLower(result.PreviousRuntime.String()),
+			Runtime:         strings.ToLower(cmd.targetFor(result.AppPrinter).Runtime.String()),
+			Outcome:         OutcomeName(result.Outcome),
+			Attempts:        result.Attempts,
+			Duration:        result.Duration.Seconds(),
+		}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		}
+
+		report.Apps = append(report.Apps, entry)
+	}
+
+	for _, app := range apps {
+		if attempted[app.Guid] {
+			continue
+		}
+
+		appPrinter := &displayhelpers.AppPrinter{
+			App:    app,
+			Spaces: spaceMap,
+		}
+		target := cmd.targetFor(appPrinter)
+		previousRuntime, _ := cmd.previousRuntime(appPrinter, target)
+
+		report.Apps = append(report.Apps, ReportEntry{
+			Guid:            app.Guid,
+			Name:            appPrinter.Name(),
+			Organization:    appPrinter.Organization(),
+			Space:           appPrinter.Space(),
+			PreviousRuntime: strings.ToLower(previousRuntime.String()),
+			Runtime:         strings.ToLower(target.Runtime.String()),
+			Outcome:         OutcomeName(NotAttempted),
+		})
+	}
+
+	sort.Sort(byLocation(report.Apps))
+
+	return report
+}
+
+type byLocation []ReportEntry
+
+func (e byLocation) Len() int      { return len(e) }
+func (e byLocation) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e byLocation) Less(i, j int) bool {
+	if e[i].Organization != e[j].Organization {
+		return e[i].Organization < e[j].Organization
+	}
+	if e[i].Space != e[j].Space {
+		return e[i].Space < e[j].Space
+	}
+	return e[i].Name < e[j].Name
+}
+
+func (r Report) Save(path string, format ReportFormat) error {
+	var (
+		body []byte
+		err  error
+	)
+
+	switch format {
+	case ReportJSON, "":
+		body, err = json.MarshalIndent(r, "", "  ")
+	case ReportCSV:
+		body, err = r.csv()
+	case ReportJUnit:
+		body, err = r.junit()
+	default:
+		return fmt.Errorf("unknown report format %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomically(path, body)
+}
+
+func (r Report) csv() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write([]string{"guid", "name", "org", "space", "previous_runtime", "runtime", "outcome", "error", "attempts", "duration_seconds"})
+	for _, entry := range r.Apps {
+		w.Write([]string{
+			entry.Guid,
+			entry.Name,
+			entry.Organization,
+			entry.Space,
+			entry.PreviousRuntime,
+			entry.Runtime,
+			entry.Outcome,
+			entry.Error,
+			strconv.Itoa(entry.Attempts),
+			formatSeconds(entry.Duration),
+		})
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Type    string `xml:"type,attr,omitempty"`
+	Message string `xml:"message,attr"`
+}
+
+// junit renders each app as a test case named after it, grouped into classes
+// by org and space. Apps that could not be migrated for lack of permissions,
+// or were not attempted at all, are skipped rather than failed.
+func (r Report) junit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:  "migrate-apps",
+		Tests: len(r.Apps),
+	}
+
+	var total float64
+	for _, entry := range r.Apps {
+		total += entry.Duration
+
+		testCase := junitTestCase{
+			ClassName: entry.Organization + "." + entry.Space,
+			Name:      entry.Name,
+			Time:      formatSeconds(entry.Duration),
+		}
+
+		message := &junitMessage{
+			Type:    entry.Outcome,
+			Message: entry.Error,
+		}
+		switch entry.Outcome {
+		case OutcomeName(Success):
+		case OutcomeName(Warning), OutcomeName(NotAttempted):
+			testCase.Skipped = message
+			suite.Skipped++
+		case OutcomeName(Err):
+			testCase.Error = message
+			suite.Errors++
+		default:
+			testCase.Failure = message
+			suite.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = formatSeconds(total)
+
+	body, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
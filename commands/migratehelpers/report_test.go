@@ -0,0 +1,144 @@
+package migratehelpers_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Report", func() {
+	var (
+		dir    string
+		path   string
+		report Report
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "report")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "report")
+
+		report = Report{
+			Apps: []ReportEntry{
+				{
+					Guid:            "app-1-guid",
+					Name:            "app-1",
+					Organization:    "some-org",
+					Space:           "some-space",
+					PreviousRuntime: "dea",
+					Runtime:         "diego",
+					Outcome:         OutcomeName(Success),
+					Attempts:        1,
+					Duration:        1.5,
+				},
+				{
+					Guid:            "app-2-guid",
+					Name:            "app-2",
+					Organization:    "some-org",
+					Space:           "some-space",
+					PreviousRuntime: "dea",
+					Runtime:         "diego",
+					Outcome:         OutcomeName(Crashed),
+					Error:           "instances crashed",
+					Attempts:        3,
+					Duration:        2,
+				},
+				{
+					Guid:            "app-3-guid",
+					Name:            "app-3",
+					Organization:    "some-org",
+					Space:           "other-space",
+					PreviousRuntime: "diego",
+					Runtime:         "dea",
+					Outcome:         OutcomeName(Warning),
+					Error:           "CF-NotAuthorized",
+					Attempts:        1,
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	read := func() string {
+		body, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	Context("as JSON", func() {
+		It("writes every app", func() {
+			err := report.Save(path, ReportJSON)
+			Expect(err).NotTo(HaveOccurred())
+
+			var saved Report
+			Expect(json.Unmarshal([]byte(read()), &saved)).To(Succeed())
+			Expect(saved).To(Equal(report))
+			Expect(read()).To(ContainSubstring(`"previous_runtime": "dea"`))
+			Expect(read()).To(ContainSubstring(`"runtime": "diego"`))
+			Expect(read()).To(ContainSubstring(`"duration_seconds": 1.5`))
+		})
+	})
+
+	Context("as CSV", func() {
+		It("writes a header and a row per app", func() {
+			err := report.Save(path, ReportCSV)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(read()).To(Equal(`guid,name,org,space,previous_runtime,runtime,outcome,error,attempts,duration_seconds
+app-1-guid,app-1,some-org,some-space,dea,diego,success,,1,1.500
+app-2-guid,app-2,some-org,some-space,dea,diego,crashed,instances crashed,3,2.000
+app-3-guid,app-3,some-org,other-space,diego,dea,warning,CF-NotAuthorized,1,0.000
+`))
+		})
+	})
+
+	Context("as JUnit XML", func() {
+		It("writes a test case per app", func() {
+			err := report.Save(path, ReportJUnit)
+			Expect(err).NotTo(HaveOccurred())
+
+			body := read()
+			Expect(body).To(HavePrefix(`<?xml version="1.0" encoding="UTF-8"?>`))
+			Expect(body).To(ContainSubstring(`<testsuite name="migrate-apps" tests="3" failures="1" errors="0" skipped="1" time="3.500">`))
+			Expect(body).To(ContainSubstring(`<testcase classname="some-org.some-space" name="app-1" time="1.500"></testcase>`))
+			Expect(body).To(ContainSubstring(`<failure type="crashed" message="instances crashed"></failure>`))
+			Expect(body).To(ContainSubstring(`<skipped type="warning" message="CF-NotAuthorized"></skipped>`))
+		})
+
+		It("skips apps that were not attempted", func() {
+			report.Apps = append(report.Apps, ReportEntry{
+				Guid:            "app-4-guid",
+				Name:            "app-4",
+				Organization:    "some-org",
+				Space:           "other-space",
+				PreviousRuntime: "dea",
+				Runtime:         "diego",
+				Outcome:         OutcomeName(NotAttempted),
+			})
+
+			err := report.Save(path, ReportJUnit)
+			Expect(err).NotTo(HaveOccurred())
+
+			body := read()
+			Expect(body).To(ContainSubstring(`tests="4" failures="1" errors="0" skipped="2"`))
+			Expect(body).To(ContainSubstring(`<skipped type="not_attempted" message=""></skipped>`))
+		})
+	})
+
+	Context("with an unknown format", func() {
+		It("returns an error", func() {
+			err := report.Save(path, ReportFormat("xml"))
+			Expect(err).To(MatchError("unknown report format xml"))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package migratehelpers
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+const maxRetryWait = 2 * time.Minute
+
+// Retry retries requests that fail with transient Cloud Controller errors.
+// The wait doubles after every attempt and is jittered so that apps migrated
+// in parallel do not retry in lockstep.
+type Retry struct {
+	Retries int
+	Wait    time.Duration
+}
+
+// DefaultRetry matches the defaults of the --retries and --retry-wait flags.
+var DefaultRetry = Retry{
+	Retries: 3,
+	Wait:    time.Second,
+}
+
+// Do calls fn until it succeeds, fails permanently or runs out of retries,
+// and returns the number of attempts made.
+func (r Retry) Do(fn func() error) (int, error) {
+	wait := r.Wait
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > r.Retries || !diegosupport.IsTransient(err) {
+			return attempt, err
+		}
+
+		time.Sleep(jitter(wait))
+
+		wait *= 2
+		if wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+	}
+}
+
+// jitter returns a random duration between half and one and a half times wait.
+func jitter(wait time.Duration) time.Duration {
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + time.Duration(rand.Int63n(int64(wait)))
+}
+
+// retryingFlagSetter retries setting the diego flag and counts the retries it
+// needed across calls.
+type retryingFlagSetter struct {
+	DiegoFlagSetter
+	retry   Retry
+	retries int
+}
+
+func (s *retryingFlagSetter) SetDiegoFlag(appGuid string, enable bool) ([]string, error) {
+	var output []string
+
+	attempts, err := s.retry.Do(func() error {
+		var err error
+		output, err = s.DiegoFlagSetter.SetDiegoFlag(appGuid, enable)
+		return err
+	})
+	s.retries += attempts - 1
+
+	return output, err
+}
+
+// retryingAppStatus retries polling the app, so that a passing Cloud
+// Controller failure is not taken for a failed migration.
+type retryingAppStatus struct {
+	AppStatusGetter
+	retry Retry
+}
+
+func (s retryingAppStatus) GetApp(appGuid string) (models.Application, error) {
+	var app models.Application
+
+	_, err := s.retry.Do(func() error {
+		var err error
+		app, err = s.AppStatusGetter.GetApp(appGuid)
+		return err
+	})
+
+	return app, err
+}
+
+func (s retryingAppStatus) GetAppInstances(appGuid string) (models.AppInstances, error) {
+	var instances models.AppInstances
+
+	_, err := s.retry.Do(func() error {
+		var err error
+		instances, err = s.AppStatusGetter.GetAppInstances(appGuid)
+		return err
+	})
+
+	return instances, err
+}
@@ -0,0 +1,68 @@
+package migratehelpers_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry", func() {
+	var (
+		retry    Retry
+		failures []error
+		calls    int
+	)
+
+	fn := func() error {
+		calls++
+		if calls <= len(failures) {
+			return failures[calls-1]
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		retry = Retry{Retries: 2, Wait: time.Millisecond}
+		calls = 0
+	})
+
+	It("does not retry a successful call", func() {
+		attempts, err := retry.Do(fn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("retries transient errors", func() {
+		failures = []error{
+			diegosupport.DiegoError{ErrorCode: "CF-ServiceUnavailable"},
+			errors.New("dial tcp: i/o timeout"),
+		}
+
+		attempts, err := retry.Do(fn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("gives up after the configured retries", func() {
+		transient := diegosupport.DiegoError{ErrorCode: "CF-ServiceUnavailable"}
+		failures = []error{transient, transient, transient, transient}
+
+		attempts, err := retry.Do(fn)
+		Expect(err).To(Equal(transient))
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("does not retry permanent errors", func() {
+		permanent := diegosupport.DiegoError{ErrorCode: "CF-NotAuthorized"}
+		failures = []error{permanent}
+
+		attempts, err := retry.Do(fn)
+		Expect(err).To(Equal(permanent))
+		Expect(attempts).To(Equal(1))
+	})
+})
@@ -0,0 +1,68 @@
+package migratehelpers
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+func needsRollback(outcome int) bool {
+	switch outcome {
+	case StagingFailed, Crashed, TimedOut, Degraded:
+		return true
+	default:
+		return false
+	}
+}
+
+// runningInstances reports how many instances of the app are running on its
+// current runtime. When that cannot be determined, the desired instance count
+// is assumed.
+func (cmd *MigrateApps) runningInstances(app models.Application, appStatus AppStatusGetter) int {
+	instances, err := appStatus.GetAppInstances(app.Guid)
+	if err != nil {
+		return app.Instances
+	}
+
+	return instances.Running()
+}
+
+// rollback moves the app back to the runtime it was migrated from and waits
+// until it is running as many instances as it was expected to run after the
+// migration.
+func (cmd *MigrateApps) rollback(
+	appPrinter *displayhelpers.AppPrinter,
+	diegoSupport DiegoFlagSetter,
+	appStatus AppStatusGetter,
+	target Target,
+	originalRuntime ui.Runtime,
+	runningBefore int,
+	failure error,
+) (int, error) {
+	migrateAppsCommand := cmd.commandFor(target)
+	migrateAppsCommand.BeforeRollback(appPrinter)
+
+	_, err := diegoSupport.SetDiegoFlag(appPrinter.App.Guid, originalRuntime == ui.Diego)
+	if err != nil {
+		migrateAppsCommand.FailRollback(appPrinter, err)
+		return RollbackFailed, rollbackError(failure, err)
+	}
+
+	result, err := cmd.waitForApp(appPrinter, appStatus, expectedRunning(appPrinter.App, runningBefore), target.Timeouts)
+	if result != Success {
+		if err == nil {
+			err = fmt.Errorf("app did not recover on %s", originalRuntime)
+		}
+		migrateAppsCommand.FailRollback(appPrinter, err)
+		return RollbackFailed, rollbackError(failure, err)
+	}
+
+	migrateAppsCommand.CompletedRollback(appPrinter)
+	return RolledBack, failure
+}
+
+func rollbackError(failure error, err error) error {
+	return fmt.Errorf("%s; rollback failed: %s", failure, err)
+}
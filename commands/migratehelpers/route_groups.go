@@ -0,0 +1,77 @@
+package migratehelpers
+
+import (
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+//go:generate counterfeiter . RouteGetter
+type RouteGetter interface {
+	GetAppRoutes(string) (models.Routes, error)
+}
+
+// RouteGroups maps the guid of every started app that shares a route with
+// another started app, e.g. either half of a blue/green pair, to a key
+// identifying the group of apps that serve the same routes.
+type RouteGroups map[string]string
+
+// FindRouteGroups reads the routes of the started apps and groups the apps
+// that share a route, directly or through another app of the group. Stopped
+// apps are not restarted by a migration, so they are left out. An app whose
+// routes cannot be read, even after retrying, is passed to unknown and
+// treated as sharing no route.
+func FindRouteGroups(apps models.Applications, routeGetter RouteGetter, retry Retry, unknown func(models.Application, error)) RouteGroups {
+	parent := map[string]string{}
+
+	var find func(guid string) string
+	find = func(guid string) string {
+		if parent[guid] == guid {
+			return guid
+		}
+		root := find(parent[guid])
+		parent[guid] = root
+		return root
+	}
+
+	routeApps := map[string]string{}
+	for _, app := range apps {
+		if app.State != models.Started {
+			continue
+		}
+
+		parent[app.Guid] = app.Guid
+
+		var routes models.Routes
+		_, err := retry.Do(func() error {
+			var err error
+			routes, err = routeGetter.GetAppRoutes(app.Guid)
+			return err
+		})
+		if err != nil {
+			unknown(app, err)
+			continue
+		}
+
+		for _, route := range routes {
+			other, ok := routeApps[route.Guid]
+			if !ok {
+				routeApps[route.Guid] = app.Guid
+				continue
+			}
+			parent[find(app.Guid)] = find(other)
+		}
+	}
+
+	sizes := map[string]int{}
+	for guid := range parent {
+		sizes[find(guid)]++
+	}
+
+	groups := RouteGroups{}
+	for guid := range parent {
+		if root := find(guid); sizes[root] > 1 {
+			groups[guid] = root
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,117 @@
+package migratehelpers_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers/migratehelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FindRouteGroups", func() {
+	var (
+		routeGetter *migratehelpersfakes.FakeRouteGetter
+		apps        models.Applications
+		appRoutes   map[string][]string
+		unknownApps []string
+	)
+
+	unknown := func(app models.Application, err error) {
+		unknownApps = append(unknownApps, app.Name)
+	}
+
+	app := func(name string, state string) models.Application {
+		return models.Application{
+			ApplicationEntity:   models.ApplicationEntity{Name: name, State: state},
+			ApplicationMetadata: models.ApplicationMetadata{Guid: name + "-guid"},
+		}
+	}
+
+	BeforeEach(func() {
+		routeGetter = new(migratehelpersfakes.FakeRouteGetter)
+		unknownApps = nil
+		apps = models.Applications{
+			app("blue", models.Started),
+			app("green", models.Started),
+			app("admin", models.Started),
+			app("worker", models.Started),
+			app("old", models.Stopped),
+		}
+		appRoutes = map[string][]string{
+			"blue-guid":   {"www-guid"},
+			"green-guid":  {"www-guid", "api-guid"},
+			"admin-guid":  {"api-guid"},
+			"worker-guid": {"worker-guid"},
+			"old-guid":    {"worker-guid"},
+		}
+		routeGetter.GetAppRoutesStub = func(appGuid string) (models.Routes, error) {
+			var routes models.Routes
+			for _, routeGuid := range appRoutes[appGuid] {
+				routes = append(routes, models.Route{RouteMetadata: models.RouteMetadata{Guid: routeGuid}})
+			}
+			return routes, nil
+		}
+	})
+
+	It("groups the started apps that share a route, directly or not", func() {
+		groups := FindRouteGroups(apps, routeGetter, Retry{}, unknown)
+
+		Expect(groups).To(HaveLen(3))
+		Expect(groups["green-guid"]).To(Equal(groups["blue-guid"]))
+		Expect(groups["admin-guid"]).To(Equal(groups["blue-guid"]))
+	})
+
+	It("leaves out stopped apps and apps not sharing a route", func() {
+		groups := FindRouteGroups(apps, routeGetter, Retry{}, unknown)
+
+		Expect(groups).NotTo(HaveKey("worker-guid"))
+		Expect(groups).NotTo(HaveKey("old-guid"))
+		Expect(routeGetter.GetAppRoutesCallCount()).To(Equal(4))
+	})
+
+	Context("when reading the routes fails transiently", func() {
+		BeforeEach(func() {
+			getRoutes := routeGetter.GetAppRoutesStub
+			calls := 0
+			routeGetter.GetAppRoutesStub = func(appGuid string) (models.Routes, error) {
+				calls++
+				if calls == 1 {
+					return nil, diegosupport.DiegoError{ErrorCode: "CF-ServiceUnavailable"}
+				}
+				return getRoutes(appGuid)
+			}
+		})
+
+		It("retries", func() {
+			groups := FindRouteGroups(apps, routeGetter, Retry{Retries: 1}, unknown)
+
+			Expect(groups).To(HaveLen(3))
+			Expect(unknownApps).To(BeEmpty())
+		})
+	})
+
+	Context("when the routes of an app cannot be read", func() {
+		BeforeEach(func() {
+			getRoutes := routeGetter.GetAppRoutesStub
+			routeGetter.GetAppRoutesStub = func(appGuid string) (models.Routes, error) {
+				if appGuid == "green-guid" {
+					return nil, errors.New("boom")
+				}
+				return getRoutes(appGuid)
+			}
+		})
+
+		It("treats the app as sharing no route", func() {
+			groups := FindRouteGroups(apps, routeGetter, Retry{}, unknown)
+
+			Expect(groups).NotTo(HaveKey("green-guid"))
+			Expect(groups).NotTo(HaveKey("blue-guid"))
+			Expect(groups).NotTo(HaveKey("admin-guid"))
+			Expect(unknownApps).To(Equal([]string{"green"}))
+		})
+	})
+})
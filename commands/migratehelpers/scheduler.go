@@ -0,0 +1,161 @@
+package migratehelpers
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+// Limits caps how many apps of a single space or org are migrated at once.
+// 0 means no limit.
+type Limits struct {
+	PerSpace int
+	PerOrg   int
+}
+
+// Scheduler hands out the apps to migrate in order, passing over apps whose
+// space or org is at its limit, or another app of whose route group is being
+// migrated, in favour of the next app that is not.
+type Scheduler struct {
+	limits      Limits
+	spaceMap    map[string]models.Space
+	routeGroups RouteGroups
+
+	mutex         sync.Mutex
+	cond          *sync.Cond
+	pending       models.Applications
+	spaceInFlight map[string]int
+	orgInFlight   map[string]int
+	groupInFlight map[string]bool
+}
+
+func NewScheduler(apps models.Applications, spaceMap map[string]models.Space, limits Limits, routeGroups RouteGroups) *Scheduler {
+	s := &Scheduler{
+		limits:        limits,
+		spaceMap:      spaceMap,
+		routeGroups:   routeGroups,
+		pending:       append(models.Applications{}, apps...),
+		spaceInFlight: map[string]int{},
+		orgInFlight:   map[string]int{},
+		groupInFlight: map[string]bool{},
+	}
+	s.cond = sync.NewCond(&s.mutex)
+	return s
+}
+
+// Next blocks until one of the pending apps may be migrated and returns it.
+// It returns false once there are no apps left or stop is closed.
+func (s *Scheduler) Next(stop <-chan struct{}) (models.Application, bool) {
+	defer wakeOnStop(s.cond, stop)()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for {
+		if isClosed(stop) || len(s.pending) == 0 {
+			return models.Application{}, false
+		}
+
+		for i, app := range s.pending {
+			if !s.eligible(app) {
+				continue
+			}
+
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			s.spaceInFlight[app.SpaceGuid]++
+			s.orgInFlight[s.orgGuid(app)]++
+			if group, ok := s.routeGroups[app.Guid]; ok {
+				s.groupInFlight[group] = true
+			}
+			return app, true
+		}
+
+		s.cond.Wait()
+	}
+}
+
+// Done makes room for another app of the app's space, org and route group.
+// Apps are only done once they are running on the target runtime or have
+// failed, so the next app of a route group waits for the previous one to be
+// healthy.
+func (s *Scheduler) Done(app models.Application) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.spaceInFlight[app.SpaceGuid]--
+	s.orgInFlight[s.orgGuid(app)]--
+	if group, ok := s.routeGroups[app.Guid]; ok {
+		delete(s.groupInFlight, group)
+	}
+	s.cond.Broadcast()
+}
+
+// Hold drops the pending apps of the app's route group and returns them, so
+// that they keep serving the group's routes when the app failed to migrate.
+func (s *Scheduler) Hold(app models.Application) models.Applications {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	group, ok := s.routeGroups[app.Guid]
+	if !ok {
+		return nil
+	}
+
+	var held models.Applications
+	var pending models.Applications
+	for _, other := range s.pending {
+		if s.routeGroups[other.Guid] == group {
+			held = append(held, other)
+		} else {
+			pending = append(pending, other)
+		}
+	}
+	s.pending = pending
+
+	s.cond.Broadcast()
+	return held
+}
+
+func (s *Scheduler) eligible(app models.Application) bool {
+	if s.limits.PerSpace > 0 && s.spaceInFlight[app.SpaceGuid] >= s.limits.PerSpace {
+		return false
+	}
+
+	if s.limits.PerOrg > 0 && s.orgInFlight[s.orgGuid(app)] >= s.limits.PerOrg {
+		return false
+	}
+
+	if group, ok := s.routeGroups[app.Guid]; ok && s.groupInFlight[group] {
+		return false
+	}
+
+	return true
+}
+
+func (s *Scheduler) orgGuid(app models.Application) string {
+	space := s.spaceMap[app.SpaceGuid]
+	if space.OrganizationGuid != "" {
+		return space.OrganizationGuid
+	}
+	return space.Organization.Guid
+}
+
+// wakeOnStop wakes the waiters on cond once stop is closed, until the
+// returned function is called.
+func wakeOnStop(cond *sync.Cond, stop <-chan struct{}) func() {
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-stop:
+			cond.L.Lock()
+			cond.Broadcast()
+			cond.L.Unlock()
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
@@ -0,0 +1,151 @@
+package migratehelpers_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+	var (
+		apps        models.Applications
+		spaceMap    map[string]models.Space
+		limits      Limits
+		routeGroups RouteGroups
+		scheduler   *Scheduler
+		stop        chan struct{}
+	)
+
+	app := func(name string, spaceGuid string) models.Application {
+		return models.Application{
+			ApplicationEntity:   models.ApplicationEntity{Name: name, SpaceGuid: spaceGuid},
+			ApplicationMetadata: models.ApplicationMetadata{Guid: name + "-guid"},
+		}
+	}
+
+	next := func() string {
+		app, ok := scheduler.Next(stop)
+		Expect(ok).To(BeTrue())
+		return app.Name
+	}
+
+	BeforeEach(func() {
+		apps = models.Applications{
+			app("a1", "space-a"),
+			app("a2", "space-a"),
+			app("b1", "space-b"),
+			app("c1", "space-c"),
+		}
+		spaceMap = map[string]models.Space{
+			"space-a": {SpaceEntity: models.SpaceEntity{OrganizationGuid: "org-1"}},
+			"space-b": {SpaceEntity: models.SpaceEntity{OrganizationGuid: "org-1"}},
+			"space-c": {SpaceEntity: models.SpaceEntity{OrganizationGuid: "org-2"}},
+		}
+		limits = Limits{}
+		routeGroups = RouteGroups{}
+		stop = make(chan struct{})
+	})
+
+	JustBeforeEach(func() {
+		scheduler = NewScheduler(apps, spaceMap, limits, routeGroups)
+	})
+
+	It("hands out the apps in order", func() {
+		Expect([]string{next(), next(), next(), next()}).To(Equal([]string{"a1", "a2", "b1", "c1"}))
+
+		_, ok := scheduler.Next(stop)
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("with a limit per space", func() {
+		BeforeEach(func() {
+			limits.PerSpace = 1
+		})
+
+		It("passes over apps of spaces at the limit", func() {
+			Expect([]string{next(), next(), next()}).To(Equal([]string{"a1", "b1", "c1"}))
+
+			waiting := make(chan string, 1)
+			go func() {
+				waiting <- next()
+			}()
+			Consistently(waiting).ShouldNot(Receive())
+
+			scheduler.Done(apps[0])
+			Eventually(waiting).Should(Receive(Equal("a2")))
+		})
+	})
+
+	Context("with a limit per org", func() {
+		BeforeEach(func() {
+			limits.PerOrg = 1
+		})
+
+		It("passes over apps of orgs at the limit", func() {
+			Expect([]string{next(), next()}).To(Equal([]string{"a1", "c1"}))
+
+			scheduler.Done(apps[0])
+			Expect(next()).To(Equal("a2"))
+		})
+	})
+
+	Context("with apps sharing a route", func() {
+		BeforeEach(func() {
+			routeGroups = RouteGroups{
+				"a1-guid": "a1-guid",
+				"c1-guid": "a1-guid",
+			}
+		})
+
+		It("migrates one app of the route group at a time", func() {
+			Expect([]string{next(), next(), next()}).To(Equal([]string{"a1", "a2", "b1"}))
+
+			waiting := make(chan string, 1)
+			go func() {
+				waiting <- next()
+			}()
+			Consistently(waiting).ShouldNot(Receive())
+
+			scheduler.Done(apps[0])
+			Eventually(waiting).Should(Receive(Equal("c1")))
+		})
+
+		It("holds back the rest of the group when an app fails", func() {
+			Expect(next()).To(Equal("a1"))
+
+			Expect(scheduler.Hold(apps[0])).To(Equal(models.Applications{apps[3]}))
+			scheduler.Done(apps[0])
+
+			Expect([]string{next(), next()}).To(Equal([]string{"a2", "b1"}))
+			_, ok := scheduler.Next(stop)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("holds back nothing for apps without a group", func() {
+			Expect(scheduler.Hold(apps[1])).To(BeEmpty())
+		})
+	})
+
+	Context("when stopped while waiting", func() {
+		BeforeEach(func() {
+			limits.PerSpace = 1
+			apps = apps[:2]
+		})
+
+		It("gives up", func() {
+			Expect(next()).To(Equal("a1"))
+
+			waiting := make(chan bool, 1)
+			go func() {
+				_, ok := scheduler.Next(stop)
+				waiting <- ok
+			}()
+			Consistently(waiting).ShouldNot(Receive())
+
+			close(stop)
+			Eventually(waiting).Should(Receive(BeFalse()))
+		})
+	})
+})
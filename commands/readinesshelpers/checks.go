@@ -0,0 +1,175 @@
+package readinesshelpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// DiskUsageThreshold is the fraction of its disk quota an instance may use
+// before it is reported.
+const DiskUsageThreshold = 0.9
+
+// DefaultDiegoStacks are the stacks Diego cells can run apps on unless the
+// checker is given others.
+var DefaultDiegoStacks = []string{"cflinuxfs2", "cflinuxfs3", "windows2012R2", "windows2016"}
+
+var dropletVariables = []string{"VCAP_APP_HOST", "VCAP_APP_PORT"}
+
+type Finding struct {
+	Severity    Severity
+	Description string
+	Remediation string
+}
+
+//go:generate counterfeiter . AppInspector
+type AppInspector interface {
+	GetAppRoutes(string) (models.Routes, error)
+	GetAppStats(string) (models.AppStats, error)
+}
+
+// Checker finds the known reasons an app may not run on Diego the way it runs
+// on the DEAs. DiegoStacks defaults to DefaultDiegoStacks.
+type Checker struct {
+	Inspector   AppInspector
+	Stacks      map[string]models.Stack
+	DiegoStacks []string
+}
+
+func (c Checker) Check(app models.Application) ([]Finding, error) {
+	var findings []Finding
+
+	findings = append(findings, c.checkRoutes(app)...)
+	findings = append(findings, checkDropletVariables(app)...)
+	findings = append(findings, c.checkStack(app)...)
+	findings = append(findings, c.checkDisk(app)...)
+
+	return findings, nil
+}
+
+// checkRoutes reports apps without routes that use the port health check.
+// DEAs only health check apps with routes, whereas Diego fails any instance
+// that does not listen on its port. Apps whose routes cannot be fetched are
+// reported as unchecked.
+func (c Checker) checkRoutes(app models.Application) []Finding {
+	if app.HealthCheckType != "" && app.HealthCheckType != "port" {
+		return nil
+	}
+
+	routes, err := c.Inspector.GetAppRoutes(app.Guid)
+	if err != nil {
+		return []Finding{{
+			Severity:    SeverityWarning,
+			Description: fmt.Sprintf("Routes could not be checked: %s", err),
+			Remediation: fmt.Sprintf("Check the routes of the app with `cf app %s`", app.Name),
+		}}
+	}
+
+	if len(routes) > 0 {
+		return nil
+	}
+
+	return []Finding{{
+		Severity:    SeverityError,
+		Description: "App has no routes and uses the port health check",
+		Remediation: fmt.Sprintf("Run `cf set-health-check %s none` if the app does not listen on a port", app.Name),
+	}}
+}
+
+// checkDropletVariables reports start commands and env vars that depend on
+// variables the DEAs set but Diego does not.
+func checkDropletVariables(app models.Application) []Finding {
+	var findings []Finding
+
+	sources := []string{"Start command", "Detected start command"}
+	values := []string{app.Command, app.DetectedStartCommand}
+
+	var names []string
+	for name := range app.EnvironmentJson {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sources = append(sources, "Env var "+name)
+		values = append(values, name+"="+fmt.Sprint(app.EnvironmentJson[name]))
+	}
+
+	for i, value := range values {
+		for _, variable := range dropletVariables {
+			if !strings.Contains(value, variable) {
+				continue
+			}
+
+			findings = append(findings, Finding{
+				Severity:    SeverityWarning,
+				Description: fmt.Sprintf("%s references %s", sources[i], variable),
+				Remediation: "Use $PORT instead of VCAP_APP_PORT and listen on 0.0.0.0 instead of VCAP_APP_HOST",
+			})
+		}
+	}
+
+	return findings
+}
+
+func (c Checker) checkStack(app models.Application) []Finding {
+	stack, ok := c.Stacks[app.StackGuid]
+	if !ok {
+		return nil
+	}
+
+	diegoStacks := c.DiegoStacks
+	if len(diegoStacks) == 0 {
+		diegoStacks = DefaultDiegoStacks
+	}
+
+	for _, name := range diegoStacks {
+		if stack.Name == name {
+			return nil
+		}
+	}
+
+	return []Finding{{
+		Severity:    SeverityError,
+		Description: fmt.Sprintf("App runs on the %s stack, which Diego does not support", stack.Name),
+		Remediation: fmt.Sprintf("Push the app to a supported stack, e.g. `cf push %s -s %s`", app.Name, diegoStacks[0]),
+	}}
+}
+
+// checkDisk reports running apps close to their disk quota. Usage cannot be
+// determined for apps without running instances, so they are not reported.
+// Apps whose stats cannot be fetched are reported as unchecked.
+func (c Checker) checkDisk(app models.Application) []Finding {
+	if app.State != models.Started {
+		return nil
+	}
+
+	stats, err := c.Inspector.GetAppStats(app.Guid)
+	if err != nil {
+		return []Finding{{
+			Severity:    SeverityWarning,
+			Description: fmt.Sprintf("Disk usage could not be checked: %s", err),
+			Remediation: fmt.Sprintf("Check the disk usage of the instances with `cf app %s`", app.Name),
+		}}
+	}
+
+	usage := stats.MaxDiskUsage()
+	if usage < DiskUsageThreshold {
+		return nil
+	}
+
+	return []Finding{{
+		Severity:    SeverityWarning,
+		Description: fmt.Sprintf("Instances use up to %.0f%% of the %dM disk quota", usage*100, app.DiskQuota),
+		Remediation: fmt.Sprintf("Increase the disk quota with `cf scale %s -k`", app.Name),
+	}}
+}
@@ -0,0 +1,174 @@
+package readinesshelpers_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/readinesshelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/readinesshelpers/readinesshelpersfakes"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		inspector *readinesshelpersfakes.FakeAppInspector
+		checker   Checker
+		app       models.Application
+
+		findings []Finding
+		err      error
+	)
+
+	BeforeEach(func() {
+		inspector = new(readinesshelpersfakes.FakeAppInspector)
+		inspector.GetAppRoutesReturns(models.Routes{
+			{RouteMetadata: models.RouteMetadata{Guid: "some-route-guid"}},
+		}, nil)
+
+		checker = Checker{
+			Inspector: inspector,
+			Stacks: map[string]models.Stack{
+				"cflinuxfs2-guid": {StackEntity: models.StackEntity{Name: "cflinuxfs2"}},
+				"lucid64-guid":    {StackEntity: models.StackEntity{Name: "lucid64"}},
+			},
+		}
+
+		app = models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				Name:            "some-app",
+				State:           models.Stopped,
+				StackGuid:       "cflinuxfs2-guid",
+				HealthCheckType: "port",
+				DiskQuota:       1024,
+			},
+			ApplicationMetadata: models.ApplicationMetadata{
+				Guid: "some-app-guid",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		findings, err = checker.Check(app)
+	})
+
+	Context("when the app has no known incompatibilities", func() {
+		It("reports nothing", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(findings).To(BeEmpty())
+			Expect(inspector.GetAppRoutesArgsForCall(0)).To(Equal("some-app-guid"))
+		})
+	})
+
+	Context("when the app has no routes", func() {
+		BeforeEach(func() {
+			inspector.GetAppRoutesReturns(models.Routes{}, nil)
+		})
+
+		It("reports the port health check as an error", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(findings).To(Equal([]Finding{{
+				Severity:    SeverityError,
+				Description: "App has no routes and uses the port health check",
+				Remediation: "Run `cf set-health-check some-app none` if the app does not listen on a port",
+			}}))
+		})
+
+		Context("when the app does not use the port health check", func() {
+			BeforeEach(func() {
+				app.HealthCheckType = "none"
+			})
+
+			It("does not look up its routes", func() {
+				Expect(findings).To(BeEmpty())
+				Expect(inspector.GetAppRoutesCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when fetching the routes fails", func() {
+		BeforeEach(func() {
+			inspector.GetAppRoutesReturns(nil, errors.New("routes failed"))
+		})
+
+		It("reports that the routes could not be checked", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Severity).To(Equal(SeverityWarning))
+			Expect(findings[0].Description).To(Equal("Routes could not be checked: routes failed"))
+		})
+	})
+
+	Context("when the start command and env reference DEA variables", func() {
+		BeforeEach(func() {
+			app.Command = "bin/server --port $VCAP_APP_PORT"
+			app.EnvironmentJson = map[string]interface{}{
+				"LISTEN": "$VCAP_APP_HOST:8080",
+				"OTHER":  "value",
+			}
+		})
+
+		It("reports each reference as a warning", func() {
+			Expect(findings).To(HaveLen(2))
+			Expect(findings[0].Severity).To(Equal(SeverityWarning))
+			Expect(findings[0].Description).To(Equal("Start command references VCAP_APP_PORT"))
+			Expect(findings[1].Description).To(Equal("Env var LISTEN references VCAP_APP_HOST"))
+		})
+	})
+
+	Context("when the app is on a stack Diego does not support", func() {
+		BeforeEach(func() {
+			app.StackGuid = "lucid64-guid"
+		})
+
+		It("reports the stack as an error", func() {
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Severity).To(Equal(SeverityError))
+			Expect(findings[0].Description).To(Equal("App runs on the lucid64 stack, which Diego does not support"))
+			Expect(findings[0].Remediation).To(ContainSubstring("-s cflinuxfs2"))
+		})
+
+		Context("when the checker is given the stacks Diego supports", func() {
+			BeforeEach(func() {
+				checker.DiegoStacks = []string{"lucid64"}
+			})
+
+			It("does not report the stack", func() {
+				Expect(findings).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("when the app is started", func() {
+		BeforeEach(func() {
+			app.State = models.Started
+			inspector.GetAppStatsReturns(models.AppStats{
+				"0": {Stats: models.InstanceStats{
+					Usage:     models.InstanceUsage{Disk: 950},
+					DiskQuota: 1000,
+				}},
+			}, nil)
+		})
+
+		It("reports disk usage close to the quota", func() {
+			Expect(inspector.GetAppStatsArgsForCall(0)).To(Equal("some-app-guid"))
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Severity).To(Equal(SeverityWarning))
+			Expect(findings[0].Description).To(Equal("Instances use up to 95% of the 1024M disk quota"))
+		})
+
+		Context("when the stats cannot be fetched", func() {
+			BeforeEach(func() {
+				inspector.GetAppStatsReturns(nil, errors.New("stats failed"))
+			})
+
+			It("reports that disk usage could not be checked", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(findings).To(HaveLen(1))
+				Expect(findings[0].Severity).To(Equal(SeverityWarning))
+				Expect(findings[0].Description).To(Equal("Disk usage could not be checked: stats failed"))
+			})
+		})
+	})
+})
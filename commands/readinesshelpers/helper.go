@@ -0,0 +1,170 @@
+package readinesshelpers
+
+import (
+	"os"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
+)
+
+type DiegoReadiness struct {
+	AppsGetterFunc   thingdoer.AppsGetterFunc
+	AppGuid          string
+	DiegoStacks      []string
+	ReadinessCommand *ui.ReadinessCommand
+}
+
+func (cmd *DiegoReadiness) Execute(cliConnection api.Connection) error {
+	cmd.ReadinessCommand.BeforeAll()
+
+	apiClient, err := api.NewClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	appRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetAppsRequest),
+	)
+
+	appPaginatedRequester, err := api.NewPaginatedRequester(cliConnection, appRequestFactory)
+	if err != nil {
+		return err
+	}
+
+	spaceRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetSpacesRequest),
+	)
+
+	spacePaginatedRequester, err := api.NewPaginatedRequester(cliConnection, spaceRequestFactory)
+	if err != nil {
+		return err
+	}
+
+	stackRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetStacksRequest),
+	)
+
+	stackPaginatedRequester, err := api.NewPaginatedRequester(cliConnection, stackRequestFactory)
+	if err != nil {
+		return err
+	}
+
+	spaces, err := thingdoer.Spaces(
+		models.SpacesParser{},
+		spacePaginatedRequester,
+	)
+	if err != nil {
+		return err
+	}
+
+	spaceMap := make(map[string]models.Space)
+	for _, space := range spaces {
+		spaceMap[space.Guid] = space
+	}
+
+	stacks, err := thingdoer.Stacks(
+		models.StacksParser{},
+		stackPaginatedRequester,
+	)
+	if err != nil {
+		return err
+	}
+
+	stackMap := make(map[string]models.Stack)
+	for _, stack := range stacks {
+		stackMap[stack.Guid] = stack
+	}
+
+	client := cloudcontroller.NewClient(cliConnection)
+
+	var apps models.Applications
+	if cmd.AppGuid != "" {
+		app, err := client.GetApp(cmd.AppGuid)
+		if err != nil {
+			return err
+		}
+		apps = models.Applications{app}
+	} else {
+		apps, err = cmd.AppsGetterFunc(
+			models.ApplicationsParser{},
+			appPaginatedRequester,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	checker := Checker{
+		Inspector:   client,
+		Stacks:      stackMap,
+		DiegoStacks: cmd.DiegoStacks,
+	}
+
+	findings, err := checkApps(checker, apps, spaceMap)
+	if err != nil {
+		return err
+	}
+
+	cmd.ReadinessCommand.AfterAll(findings, len(apps))
+
+	return nil
+}
+
+func checkApps(checker Checker, apps models.Applications, spaceMap map[string]models.Space) ([]ui.ReadinessFinding, error) {
+	var findings []ui.ReadinessFinding
+
+	for _, app := range apps {
+		appFindings, err := checker.Check(app)
+		if err != nil {
+			return nil, err
+		}
+
+		appPrinter := &displayhelpers.AppPrinter{
+			App:    app,
+			Spaces: spaceMap,
+		}
+		for _, finding := range appFindings {
+			findings = append(findings, ui.ReadinessFinding{
+				App:         appPrinter,
+				Severity:    string(finding.Severity),
+				Description: finding.Description,
+				Remediation: finding.Remediation,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func NewReadinessCommand(cliConnection api.Connection, orgName string, spaceName string, appName string) (ui.ReadinessCommand, error) {
+	username, err := cliConnection.Username()
+	if err != nil {
+		return ui.ReadinessCommand{}, err
+	}
+
+	if spaceName != "" {
+		space, err := cliConnection.GetSpace(spaceName)
+		if err != nil || space.Guid == "" {
+			return ui.ReadinessCommand{}, err
+		}
+		orgName = space.Organization.Name
+	}
+
+	traceEnv := os.Getenv("CF_TRACE")
+	traceLogger := trace.NewLogger(false, traceEnv, "")
+	tUI := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+
+	return ui.ReadinessCommand{
+		Username:     username,
+		Organization: orgName,
+		Space:        spaceName,
+		AppName:      appName,
+		UI:           tUI,
+	}, nil
+}
@@ -0,0 +1,13 @@
+package readinesshelpers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestReadinesshelpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Readinesshelpers Suite")
+}
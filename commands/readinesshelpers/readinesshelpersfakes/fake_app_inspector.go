@@ -0,0 +1,98 @@
+// This file was generated by counterfeiter
+package readinesshelpersfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/readinesshelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+type FakeAppInspector struct {
+	GetAppRoutesStub        func(string) (models.Routes, error)
+	getAppRoutesMutex       sync.RWMutex
+	getAppRoutesArgsForCall []struct {
+		arg1 string
+	}
+	getAppRoutesReturns struct {
+		result1 models.Routes
+		result2 error
+	}
+	GetAppStatsStub        func(string) (models.AppStats, error)
+	getAppStatsMutex       sync.RWMutex
+	getAppStatsArgsForCall []struct {
+		arg1 string
+	}
+	getAppStatsReturns struct {
+		result1 models.AppStats
+		result2 error
+	}
+}
+
+func (fake *FakeAppInspector) GetAppRoutes(arg1 string) (models.Routes, error) {
+	fake.getAppRoutesMutex.Lock()
+	fake.getAppRoutesArgsForCall = append(fake.getAppRoutesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppRoutesMutex.Unlock()
+	if fake.GetAppRoutesStub != nil {
+		return fake.GetAppRoutesStub(arg1)
+	} else {
+		return fake.getAppRoutesReturns.result1, fake.getAppRoutesReturns.result2
+	}
+}
+
+func (fake *FakeAppInspector) GetAppRoutesCallCount() int {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return len(fake.getAppRoutesArgsForCall)
+}
+
+func (fake *FakeAppInspector) GetAppRoutesArgsForCall(i int) string {
+	fake.getAppRoutesMutex.RLock()
+	defer fake.getAppRoutesMutex.RUnlock()
+	return fake.getAppRoutesArgsForCall[i].arg1
+}
+
+func (fake *FakeAppInspector) GetAppRoutesReturns(result1 models.Routes, result2 error) {
+	fake.GetAppRoutesStub = nil
+	fake.getAppRoutesReturns = struct {
+		result1 models.Routes
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppInspector) GetAppStats(arg1 string) (models.AppStats, error) {
+	fake.getAppStatsMutex.Lock()
+	fake.getAppStatsArgsForCall = append(fake.getAppStatsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.getAppStatsMutex.Unlock()
+	if fake.GetAppStatsStub != nil {
+		return fake.GetAppStatsStub(arg1)
+	} else {
+		return fake.getAppStatsReturns.result1, fake.getAppStatsReturns.result2
+	}
+}
+
+func (fake *FakeAppInspector) GetAppStatsCallCount() int {
+	fake.getAppStatsMutex.RLock()
+	defer fake.getAppStatsMutex.RUnlock()
+	return len(fake.getAppStatsArgsForCall)
+}
+
+func (fake *FakeAppInspector) GetAppStatsArgsForCall(i int) string {
+	fake.getAppStatsMutex.RLock()
+	defer fake.getAppStatsMutex.RUnlock()
+	return fake.getAppStatsArgsForCall[i].arg1
+}
+
+func (fake *FakeAppInspector) GetAppStatsReturns(result1 models.AppStats, result2 error) {
+	fake.GetAppStatsStub = nil
+	fake.getAppStatsReturns = struct {
+		result1 models.AppStats
+		result2 error
+	}{result1, result2}
+}
+
+var _ readinesshelpers.AppInspector = new(FakeAppInspector)
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/flaghelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/snapshothelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+type RuntimeSnapshotCommand struct {
+	Save    RuntimeSnapshotSaveCommand    `command:"save" description:"Save the runtime of every visible app to a file"`
+	Diff    RuntimeSnapshotDiffCommand    `command:"diff" description:"Show the apps that changed runtime, appeared or disappeared since a snapshot"`
+	Restore RuntimeSnapshotRestoreCommand `command:"restore" description:"Move every app in a snapshot back to the runtime recorded for it"`
+}
+
+type RuntimeSnapshotSaveCommand struct {
+	RequiredOptions RuntimeSnapshotSavePositionalArgs `positional-args:"yes"`
+}
+
+type RuntimeSnapshotSavePositionalArgs struct {
+	File string `positional-arg-name:"FILE" required:"true" description:"The file to save the snapshot to"`
+}
+
+func (command RuntimeSnapshotSaveCommand) Execute([]string) error {
+	cliConnection := DiegoEnabler.CLIConnection
+
+	runtimeSnapshotCommand, err := snapshothelpers.NewRuntimeSnapshotCommand(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	cmd := snapshothelpers.SaveSnapshot{
+		Path:                   command.RequiredOptions.File,
+		RuntimeSnapshotCommand: &runtimeSnapshotCommand,
+	}
+
+	return cmd.Execute(cliConnection)
+}
+
+type RuntimeSnapshotDiffCommand struct {
+	RequiredOptions RuntimeSnapshotDiffPositionalArgs `positional-args:"yes"`
+}
+
+type RuntimeSnapshotDiffPositionalArgs struct {
+	Old string `positional-arg-name:"OLD" required:"true" description:"The earlier snapshot"`
+	New string `positional-arg-name:"NEW" description:"The later snapshot; the running apps when omitted"`
+}
+
+func (command RuntimeSnapshotDiffCommand) Execute([]string) error {
+	cliConnection := DiegoEnabler.CLIConnection
+
+	runtimeSnapshotCommand, err := snapshothelpers.NewRuntimeSnapshotCommand(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	cmd := snapshothelpers.DiffSnapshots{
+		OldPath:                command.RequiredOptions.Old,
+		NewPath:                command.RequiredOptions.New,
+		RuntimeSnapshotCommand: &runtimeSnapshotCommand,
+	}
+
+	return cmd.Execute(cliConnection)
+}
+
+type RuntimeSnapshotRestoreCommand struct {
+	RequiredOptions RuntimeSnapshotRestorePositionalArgs `positional-args:"yes"`
+	OnlyChanged     bool                                 `long:"only-changed" description:"Only migrate the apps no longer on the runtime recorded for them"`
+	MaxInFlight     flaghelpers.ParallelFlag             `short:"p" value-name:"MAX_IN_FLIGHT" default:"1" description:"Maximum number of apps to migrate in parallel (maximum: 100)"`
+	MaxMemory       flaghelpers.MemoryFlag               `long:"max-memory-in-flight" value-name:"MEMORY" description:"Maximum memory, summed over the instances of the apps being migrated in parallel, e.g. 64G"`
+	MaxPerSpace     int                                  `long:"max-per-space" value-name:"MAX_PER_SPACE" description:"Maximum number of apps of a single space to migrate in parallel"`
+	MaxPerOrg       int                                  `long:"max-per-org" value-name:"MAX_PER_ORG" description:"Maximum number of apps of a single org to migrate in parallel"`
+	Rollback        bool                                 `long:"rollback-on-failure" description:"Move apps that fail to start, or run fewer instances than before, back to the runtime they were on"`
+	DryRun          bool                                 `long:"dry-run" description:"List the apps that would be migrated without migrating them"`
+	Report          string                               `long:"report" value-name:"FILE" description:"Write the outcome of migrating each app to FILE"`
+	ReportFormat    string                               `long:"report-format" value-name:"FORMAT" choice:"json" choice:"csv" choice:"junit" default:"json" description:"Format of the report: json, csv or junit"`
+	Retries         int                                  `long:"retries" value-name:"RETRIES" default:"3" description:"Number of times to retry a request that fails with a transient Cloud Controller error"`
+	RetryWait       time.Duration                        `long:"retry-wait" value-name:"WAIT" default:"1s" description:"Time to wait before the first retry; the wait doubles with every retry"`
+	Timeout         time.Duration                        `long:"timeout" value-name:"TIMEOUT" description:"Time to wait for each app's instances to start, instead of a time derived from the app's health check timeout and instance count; CF_STARTUP_TIMEOUT is not used"`
+	Multiplier      float64                              `long:"timeout-multiplier" value-name:"MULTIPLIER" default:"1" description:"Factor to scale the time derived for each app to start by"`
+}
+
+type RuntimeSnapshotRestorePositionalArgs struct {
+	File string `positional-arg-name:"FILE" required:"true" description:"The snapshot to restore"`
+}
+
+func (command RuntimeSnapshotRestoreCommand) Execute([]string) error {
+	cliConnection := DiegoEnabler.CLIConnection
+
+	if command.MaxPerSpace < 0 || command.MaxPerOrg < 0 {
+		return errorhelpers.InvalidConcurrencyLimitError
+	}
+
+	if command.Multiplier <= 0 {
+		return errorhelpers.InvalidTimeoutMultiplierError
+	}
+
+	if command.Timeout > 0 && command.Multiplier != 1 {
+		return errorhelpers.TimeoutAndMultiplierError
+	}
+
+	snapshot, err := snapshothelpers.LoadSnapshot(command.RequiredOptions.File)
+	if err != nil {
+		return err
+	}
+
+	// Every app is moved to the runtime recorded for it, so the runtime
+	// passed here is never used on its own.
+	migrateAppsCommand, err := migratehelpers.NewMigrateAppsCommand(cliConnection, "", "", ui.DEA)
+	if err != nil {
+		return err
+	}
+	migrateAppsCommand.Snapshot = command.RequiredOptions.File
+
+	runtimeSnapshotCommand, err := snapshothelpers.NewRuntimeSnapshotCommand(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	// CF_STARTUP_TIMEOUT is a single wait for every app, so only --timeout
+	// replaces the time derived for each app.
+	timeouts := migratehelpers.Timeouts{
+		Staging: migratehelpers.TimeoutsFromEnv().Staging,
+		Startup: command.Timeout,
+	}
+
+	cmd := snapshothelpers.Restore{
+		Snapshot:    snapshot,
+		OnlyChanged: command.OnlyChanged,
+		MigrateApps: &migratehelpers.MigrateApps{
+			MaxInFlight:       command.MaxInFlight.Value,
+			MaxMemoryInFlight: command.MaxMemory.Megabytes,
+			Limits: migratehelpers.Limits{
+				PerSpace: command.MaxPerSpace,
+				PerOrg:   command.MaxPerOrg,
+			},
+			Runtime:            ui.DEA,
+			MigrateAppsCommand: &migrateAppsCommand,
+			Timeouts:           timeouts,
+			TimeoutMultiplier:  command.Multiplier,
+			PollInterval:       migratehelpers.DefaultPollInterval,
+			RollbackOnFailure:  command.Rollback,
+			DryRun:             command.DryRun,
+			Report:             command.Report,
+			ReportFormat:       migratehelpers.ReportFormat(command.ReportFormat),
+			Retry: migratehelpers.Retry{
+				Retries: command.Retries,
+				Wait:    command.RetryWait,
+			},
+		},
+		RuntimeSnapshotCommand: &runtimeSnapshotCommand,
+	}
+
+	return cmd.Execute(cliConnection)
+}
@@ -0,0 +1,77 @@
+package commands_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/errorhelpers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RuntimeSnapshotRestoreCommand", func() {
+	var (
+		command RuntimeSnapshotRestoreCommand
+
+		err error
+	)
+
+	JustBeforeEach(func() {
+		err = command.Execute([]string{})
+	})
+
+	Context("when a concurrency limit is negative", func() {
+		BeforeEach(func() {
+			command = RuntimeSnapshotRestoreCommand{
+				RequiredOptions: RuntimeSnapshotRestorePositionalArgs{File: "snapshot.json"},
+				MaxPerOrg:       -1,
+				Multiplier:      1,
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.InvalidConcurrencyLimitError))
+		})
+	})
+
+	Context("when the timeout multiplier is not positive", func() {
+		BeforeEach(func() {
+			command = RuntimeSnapshotRestoreCommand{
+				RequiredOptions: RuntimeSnapshotRestorePositionalArgs{File: "snapshot.json"},
+				Multiplier:      0,
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.InvalidTimeoutMultiplierError))
+		})
+	})
+
+	Context("when both a timeout and a timeout multiplier are passed", func() {
+		BeforeEach(func() {
+			command = RuntimeSnapshotRestoreCommand{
+				RequiredOptions: RuntimeSnapshotRestorePositionalArgs{File: "snapshot.json"},
+				Timeout:         time.Minute,
+				Multiplier:      2,
+			}
+		})
+
+		It("returns an error", func() {
+			Expect(err).To(Equal(errorhelpers.TimeoutAndMultiplierError))
+		})
+	})
+
+	Context("when the snapshot does not exist", func() {
+		BeforeEach(func() {
+			command = RuntimeSnapshotRestoreCommand{
+				RequiredOptions: RuntimeSnapshotRestorePositionalArgs{File: "/does/not/exist.json"},
+				Multiplier:      1,
+			}
+		})
+
+		It("returns an error before contacting the Cloud Controller", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
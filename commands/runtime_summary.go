@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/diegohelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/summaryhelpers"
+)
+
+type RuntimeSummaryCommand struct {
+	Organization string `short:"o" value-name:"ORG" description:"Organization to restrict the summary to"`
+}
+
+func (command RuntimeSummaryCommand) Execute([]string) error {
+	cliConnection := DiegoEnabler.CLIConnection
+
+	cmd := summaryhelpers.RuntimeSummary{}
+
+	if command.Organization != "" {
+		org, err := cliConnection.GetOrg(command.Organization)
+		if err != nil || org.Guid == "" {
+			return diegohelpers.OrgNotFoundErr{OrganizationName: command.Organization}
+		}
+		cmd.OrganizationGuid = org.Guid
+	}
+
+	runtimeSummaryCommand, err := summaryhelpers.NewRuntimeSummaryCommand(cliConnection, command.Organization)
+	if err != nil {
+		return err
+	}
+	cmd.RuntimeSummaryCommand = &runtimeSummaryCommand
+
+	return cmd.Execute(cliConnection)
+}
@@ -0,0 +1,133 @@
+package snapshothelpers
+
+import (
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
+)
+
+type SaveSnapshot struct {
+	Path                   string
+	RuntimeSnapshotCommand *ui.RuntimeSnapshotCommand
+}
+
+func (cmd *SaveSnapshot) Execute(cliConnection api.Connection) error {
+	cmd.RuntimeSnapshotCommand.BeforeSave(cmd.Path)
+
+	snapshot, err := TakeSnapshot(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	err = snapshot.Save(cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	cmd.RuntimeSnapshotCommand.AfterSave(len(snapshot.Apps))
+
+	return nil
+}
+
+// DiffSnapshots compares the old snapshot to the new one, or to the apps
+// running now when there is no new one.
+type DiffSnapshots struct {
+	OldPath                string
+	NewPath                string
+	RuntimeSnapshotCommand *ui.RuntimeSnapshotCommand
+}
+
+func (cmd *DiffSnapshots) Execute(cliConnection api.Connection) error {
+	cmd.RuntimeSnapshotCommand.BeforeDiff(cmd.OldPath, cmd.NewPath)
+
+	older, err := LoadSnapshot(cmd.OldPath)
+	if err != nil {
+		return err
+	}
+
+	var newer Snapshot
+	if cmd.NewPath != "" {
+		newer, err = LoadSnapshot(cmd.NewPath)
+	} else {
+		newer, err = TakeSnapshot(cliConnection)
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd.RuntimeSnapshotCommand.AfterDiff(Diff(older, newer))
+
+	return nil
+}
+
+// TakeSnapshot records every app visible to the user, on either runtime.
+func TakeSnapshot(cliConnection api.Connection) (Snapshot, error) {
+	apiClient, err := api.NewClient(cliConnection)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	appRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetAppsRequest),
+	)
+
+	appPaginatedRequester, err := api.NewPaginatedRequester(cliConnection, appRequestFactory)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	spaceRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetSpacesRequest),
+	)
+
+	spacePaginatedRequester, err := api.NewPaginatedRequester(cliConnection, spaceRequestFactory)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	spaces, err := thingdoer.Spaces(
+		models.SpacesParser{},
+		spacePaginatedRequester,
+	)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	spaceMap := make(map[string]models.Space)
+	for _, space := range spaces {
+		spaceMap[space.Guid] = space
+	}
+
+	apps, err := thingdoer.Apps(
+		models.ApplicationsParser{},
+		appPaginatedRequester,
+		api.Filters{},
+	)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return NewSnapshot(apps, spaceMap, time.Now().UTC()), nil
+}
+
+func NewRuntimeSnapshotCommand(cliConnection api.Connection) (ui.RuntimeSnapshotCommand, error) {
+	username, err := cliConnection.Username()
+	if err != nil {
+		return ui.RuntimeSnapshotCommand{}, err
+	}
+
+	traceEnv := os.Getenv("CF_TRACE")
+	traceLogger := trace.NewLogger(false, traceEnv, "")
+	tUI := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+
+	return ui.RuntimeSnapshotCommand{
+		Username: username,
+		UI:       tUI,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package snapshothelpers
+
+import (
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+// Restore puts the apps of a snapshot back on the runtime recorded for them,
+// migrating them the way migrate-apps does. Apps deleted since the snapshot
+// was taken are left out and listed at the end.
+type Restore struct {
+	Snapshot               Snapshot
+	OnlyChanged            bool
+	MigrateApps            *migratehelpers.MigrateApps
+	RuntimeSnapshotCommand *ui.RuntimeSnapshotCommand
+}
+
+func (cmd *Restore) Execute(cliConnection api.Connection) error {
+	var missing []SnapshotApp
+
+	cmd.MigrateApps.AppsGetterFunc = func(appsParser thingdoer.ApplicationsParser, paginatedRequester thingdoer.PaginatedRequester) (models.Applications, error) {
+		apps, err := thingdoer.Apps(appsParser, paginatedRequester, api.Filters{})
+		if err != nil {
+			return nil, err
+		}
+
+		var selected models.Applications
+		selected, missing = cmd.Snapshot.Select(apps, cmd.OnlyChanged)
+		return selected, nil
+	}
+	cmd.MigrateApps.Targets = cmd.Snapshot.Targets(cmd.MigrateApps.Timeouts)
+
+	err := cmd.MigrateApps.Execute(cliConnection)
+
+	if len(missing) > 0 {
+		var displayed []ui.SnapshotApp
+		for _, app := range missing {
+			displayed = append(displayed, app.display())
+		}
+		cmd.RuntimeSnapshotCommand.MissingApps(displayed)
+	}
+
+	return err
+}
+
+// Select returns the apps that are in the snapshot, in the order they are
+// given, along with the apps of the snapshot that are not among them. With
+// onlyChanged, apps already on the runtime recorded for them are left out.
+func (s Snapshot) Select(apps models.Applications, onlyChanged bool) (models.Applications, []SnapshotApp) {
+	recorded := make(map[string]SnapshotApp)
+	for _, app := range s.Apps {
+		recorded[app.Guid] = app
+	}
+
+	var selected models.Applications
+	found := make(map[string]bool)
+	for _, app := range apps {
+		snapshotApp, ok := recorded[app.Guid]
+		if !ok {
+			continue
+		}
+		found[app.Guid] = true
+
+		if onlyChanged && app.Diego == snapshotApp.Diego {
+			continue
+		}
+		selected = append(selected, app)
+	}
+
+	var missing []SnapshotApp
+	for _, app := range sortedApps(s.Apps) {
+		if !found[app.Guid] {
+			missing = append(missing, app)
+		}
+	}
+
+	return selected, missing
+}
+
+// Targets maps the guid of every app in the snapshot to the runtime recorded
+// for it.
+func (s Snapshot) Targets(timeouts migratehelpers.Timeouts) map[string]migratehelpers.Target {
+	targets := make(map[string]migratehelpers.Target)
+	for _, app := range s.Apps {
+		targets[app.Guid] = migratehelpers.Target{
+			Runtime:  app.Runtime(),
+			Timeouts: timeouts,
+		}
+	}
+	return targets
+}
@@ -0,0 +1,80 @@
+package snapshothelpers_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/snapshothelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restore", func() {
+	var snapshot Snapshot
+
+	newApp := func(guid string, diego bool) models.Application {
+		return models.Application{
+			ApplicationMetadata: models.ApplicationMetadata{Guid: guid},
+			ApplicationEntity:   models.ApplicationEntity{Name: guid, Diego: diego},
+		}
+	}
+
+	BeforeEach(func() {
+		snapshot = Snapshot{
+			Apps: []SnapshotApp{
+				{Guid: "flipped-guid", Name: "flipped", Organization: "org", Space: "space"},
+				{Guid: "unchanged-guid", Name: "unchanged", Organization: "org", Space: "space", Diego: true},
+				{Guid: "deleted-guid", Name: "deleted", Organization: "org", Space: "space"},
+			},
+		}
+	})
+
+	Describe("Select", func() {
+		var apps models.Applications
+
+		BeforeEach(func() {
+			apps = models.Applications{
+				newApp("unchanged-guid", true),
+				newApp("pushed-since-guid", true),
+				newApp("flipped-guid", true),
+			}
+		})
+
+		It("selects the apps in the snapshot and lists the ones that no longer exist", func() {
+			selected, missing := snapshot.Select(apps, false)
+
+			Expect(selected).To(Equal(models.Applications{
+				newApp("unchanged-guid", true),
+				newApp("flipped-guid", true),
+			}))
+			Expect(missing).To(Equal([]SnapshotApp{
+				{Guid: "deleted-guid", Name: "deleted", Organization: "org", Space: "space"},
+			}))
+		})
+
+		Context("when only restoring changed apps", func() {
+			It("leaves out the apps already on the recorded runtime", func() {
+				selected, _ := snapshot.Select(apps, true)
+
+				Expect(selected).To(Equal(models.Applications{
+					newApp("flipped-guid", true),
+				}))
+			})
+		})
+	})
+
+	Describe("Targets", func() {
+		It("targets the runtime recorded for each app", func() {
+			timeouts := migratehelpers.Timeouts{Startup: time.Minute}
+
+			Expect(snapshot.Targets(timeouts)).To(Equal(map[string]migratehelpers.Target{
+				"flipped-guid":   {Runtime: ui.DEA, Timeouts: timeouts},
+				"unchanged-guid": {Runtime: ui.Diego, Timeouts: timeouts},
+				"deleted-guid":   {Runtime: ui.DEA, Timeouts: timeouts},
+			}))
+		})
+	})
+})
@@ -0,0 +1,171 @@
+package snapshothelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/migratehelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+)
+
+// Snapshot records the runtime of every app visible to the user at one point
+// in time.
+type Snapshot struct {
+	TakenAt time.Time     `json:"taken_at"`
+	Apps    []SnapshotApp `json:"apps"`
+}
+
+type SnapshotApp struct {
+	Guid             string `json:"guid"`
+	Name             string `json:"name"`
+	Organization     string `json:"org"`
+	OrganizationGuid string `json:"org_guid"`
+	Space            string `json:"space"`
+	SpaceGuid        string `json:"space_guid"`
+	State            string `json:"state"`
+	Diego            bool   `json:"diego"`
+}
+
+func (a SnapshotApp) Runtime() ui.Runtime {
+	if a.Diego {
+		return ui.Diego
+	}
+	return ui.DEA
+}
+
+func (a SnapshotApp) display() ui.SnapshotApp {
+	return ui.SnapshotApp{
+		Organization: a.Organization,
+		Space:        a.Space,
+		Name:         a.Name,
+		Runtime:      a.Runtime(),
+	}
+}
+
+// NewSnapshot records the apps ordered by org, space and name, so that
+// snapshots of the same foundation can be compared with a plain diff too.
+func NewSnapshot(apps models.Applications, spaceMap map[string]models.Space, takenAt time.Time) Snapshot {
+	snapshot := Snapshot{
+		TakenAt: takenAt,
+		Apps:    []SnapshotApp{},
+	}
+
+	for _, app := range apps {
+		space := spaceMap[app.SpaceGuid]
+
+		organizationGuid := space.OrganizationGuid
+		if organizationGuid == "" {
+			organizationGuid = space.Organization.Guid
+		}
+
+		snapshot.Apps = append(snapshot.Apps, SnapshotApp{
+			Guid:             app.Guid,
+			Name:             app.Name,
+			Organization:     space.Organization.Name,
+			OrganizationGuid: organizationGuid,
+			Space:            space.Name,
+			SpaceGuid:        app.SpaceGuid,
+			State:            app.State,
+			Diego:            app.Diego,
+		})
+	}
+
+	sortApps(snapshot.Apps)
+
+	return snapshot
+}
+
+func LoadSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	err = json.Unmarshal(body, &snapshot)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("Invalid snapshot %s: %s", path, err)
+	}
+
+	for _, app := range snapshot.Apps {
+		if app.Guid == "" {
+			return Snapshot{}, fmt.Errorf("Invalid snapshot %s: app %s/%s/%s has no guid", path, app.Organization, app.Space, app.Name)
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (s Snapshot) Save(path string) error {
+	body, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return migratehelpers.WriteFileAtomically(path, append(body, '\n'))
+}
+
+// Diff matches apps by guid, so that an app renamed or moved between the
+// snapshots is not reported as one app disappearing and another appearing.
+// Changed apps are reported as they are in the newer snapshot.
+func Diff(older Snapshot, newer Snapshot) ui.SnapshotDiff {
+	var diff ui.SnapshotDiff
+
+	olderApps := make(map[string]SnapshotApp)
+	for _, app := range older.Apps {
+		olderApps[app.Guid] = app
+	}
+
+	newerGuids := make(map[string]bool)
+	for _, app := range sortedApps(newer.Apps) {
+		newerGuids[app.Guid] = true
+
+		olderApp, ok := olderApps[app.Guid]
+		switch {
+		case !ok:
+			diff.Appeared = append(diff.Appeared, app.display())
+		case !olderApp.Diego && app.Diego:
+			diff.ToDiego = append(diff.ToDiego, app.display())
+		case olderApp.Diego && !app.Diego:
+			diff.ToDEA = append(diff.ToDEA, app.display())
+		}
+	}
+
+	for _, app := range sortedApps(older.Apps) {
+		if !newerGuids[app.Guid] {
+			diff.Disappeared = append(diff.Disappeared, app.display())
+		}
+	}
+
+	return diff
+}
+
+func sortedApps(apps []SnapshotApp) []SnapshotApp {
+	sorted := make([]SnapshotApp, len(apps))
+	copy(sorted, apps)
+	sortApps(sorted)
+	return sorted
+}
+
+func sortApps(apps []SnapshotApp) {
+	sort.Stable(byLocation(apps))
+}
+
+type byLocation []SnapshotApp
+
+func (a byLocation) Len() int      { return len(a) }
+func (a byLocation) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byLocation) Less(i, j int) bool {
+	if a[i].Organization != a[j].Organization {
+		return a[i].Organization < a[j].Organization
+	}
+	if a[i].Space != a[j].Space {
+		return a[i].Space < a[j].Space
+	}
+	return a[i].Name < a[j].Name
+}
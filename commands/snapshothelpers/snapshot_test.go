@@ -0,0 +1,147 @@
+package snapshothelpers_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/snapshothelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "snapshot")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("NewSnapshot", func() {
+		It("records each app with its org, space, state and runtime, ordered by location", func() {
+			spaceMap := map[string]models.Space{
+				"space-guid": {
+					SpaceEntity: models.SpaceEntity{
+						Name:             "some-space",
+						OrganizationGuid: "org-guid",
+						Organization: models.Organization{
+							OrganizationEntity: models.OrganizationEntity{Name: "some-org"},
+						},
+					},
+					SpaceMetadata: models.SpaceMetadata{Guid: "space-guid"},
+				},
+			}
+			apps := models.Applications{
+				{
+					ApplicationMetadata: models.ApplicationMetadata{Guid: "guid-b"},
+					ApplicationEntity:   models.ApplicationEntity{Name: "b-app", SpaceGuid: "space-guid", State: models.Started, Diego: true},
+				},
+				{
+					ApplicationMetadata: models.ApplicationMetadata{Guid: "guid-a"},
+					ApplicationEntity:   models.ApplicationEntity{Name: "a-app", SpaceGuid: "space-guid", State: models.Stopped},
+				},
+			}
+			takenAt := time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC)
+
+			snapshot := NewSnapshot(apps, spaceMap, takenAt)
+
+			Expect(snapshot.TakenAt).To(Equal(takenAt))
+			Expect(snapshot.Apps).To(Equal([]SnapshotApp{
+				{Guid: "guid-a", Name: "a-app", Organization: "some-org", OrganizationGuid: "org-guid", Space: "some-space", SpaceGuid: "space-guid", State: models.Stopped, Diego: false},
+				{Guid: "guid-b", Name: "b-app", Organization: "some-org", OrganizationGuid: "org-guid", Space: "some-space", SpaceGuid: "space-guid", State: models.Started, Diego: true},
+			}))
+		})
+	})
+
+	Describe("Save and LoadSnapshot", func() {
+		It("round-trips the snapshot", func() {
+			snapshot := Snapshot{
+				TakenAt: time.Date(2016, 5, 1, 12, 0, 0, 0, time.UTC),
+				Apps: []SnapshotApp{
+					{Guid: "guid-a", Name: "a-app", Organization: "some-org", Space: "some-space", State: models.Started, Diego: true},
+				},
+			}
+			path := filepath.Join(dir, "snapshot.json")
+
+			Expect(snapshot.Save(path)).To(Succeed())
+
+			loaded, err := LoadSnapshot(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(snapshot))
+		})
+
+		It("leaves no temporary files behind", func() {
+			path := filepath.Join(dir, "snapshot.json")
+			Expect(Snapshot{}.Save(path)).To(Succeed())
+
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+			Expect(files[0].Name()).To(Equal("snapshot.json"))
+		})
+
+		It("rejects files that are not snapshots", func() {
+			path := filepath.Join(dir, "snapshot.json")
+			Expect(ioutil.WriteFile(path, []byte("not json"), 0644)).To(Succeed())
+
+			_, err := LoadSnapshot(path)
+			Expect(err).To(MatchError(ContainSubstring("Invalid snapshot")))
+		})
+
+		It("rejects apps without a guid", func() {
+			path := filepath.Join(dir, "snapshot.json")
+			Expect(ioutil.WriteFile(path, []byte(`{"apps": [{"name": "some-app"}]}`), 0644)).To(Succeed())
+
+			_, err := LoadSnapshot(path)
+			Expect(err).To(MatchError(ContainSubstring("has no guid")))
+		})
+	})
+
+	Describe("Diff", func() {
+		It("reports apps that changed runtime, appeared or disappeared", func() {
+			older := Snapshot{
+				Apps: []SnapshotApp{
+					{Guid: "migrated-guid", Name: "migrated", Organization: "org", Space: "space"},
+					{Guid: "flipped-back-guid", Name: "flipped-back", Organization: "org", Space: "space", Diego: true},
+					{Guid: "unchanged-guid", Name: "unchanged", Organization: "org", Space: "space", Diego: true},
+					{Guid: "deleted-guid", Name: "deleted", Organization: "org", Space: "space"},
+				},
+			}
+			newer := Snapshot{
+				Apps: []SnapshotApp{
+					{Guid: "migrated-guid", Name: "migrated", Organization: "org", Space: "space", Diego: true},
+					{Guid: "flipped-back-guid", Name: "renamed", Organization: "org", Space: "other-space"},
+					{Guid: "unchanged-guid", Name: "unchanged", Organization: "org", Space: "space", Diego: true},
+					{Guid: "pushed-guid", Name: "pushed", Organization: "org", Space: "space", Diego: true},
+				},
+			}
+
+			diff := Diff(older, newer)
+
+			Expect(diff).To(Equal(ui.SnapshotDiff{
+				ToDiego:     []ui.SnapshotApp{{Organization: "org", Space: "space", Name: "migrated", Runtime: ui.Diego}},
+				ToDEA:       []ui.SnapshotApp{{Organization: "org", Space: "other-space", Name: "renamed", Runtime: ui.DEA}},
+				Appeared:    []ui.SnapshotApp{{Organization: "org", Space: "space", Name: "pushed", Runtime: ui.Diego}},
+				Disappeared: []ui.SnapshotApp{{Organization: "org", Space: "space", Name: "deleted", Runtime: ui.DEA}},
+			}))
+		})
+
+		It("is empty when nothing changed", func() {
+			snapshot := Snapshot{
+				Apps: []SnapshotApp{{Guid: "some-guid", Name: "some-app"}},
+			}
+
+			Expect(Diff(snapshot, snapshot).Empty()).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package snapshothelpers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSnapshothelpers(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Snapshothelpers Suite")
+}
@@ -0,0 +1,186 @@
+package summaryhelpers
+
+import (
+	"os"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+	"github.com/cloudfoundry/cli/cf/terminal"
+	"github.com/cloudfoundry/cli/cf/trace"
+)
+
+type RuntimeSummary struct {
+	OrganizationGuid      string
+	RuntimeSummaryCommand *ui.RuntimeSummaryCommand
+}
+
+// Execute fetches every app once, across both runtimes, rather than once per
+// runtime.
+func (cmd *RuntimeSummary) Execute(cliConnection api.Connection) error {
+	cmd.RuntimeSummaryCommand.BeforeAll()
+
+	apiClient, err := api.NewClient(cliConnection)
+	if err != nil {
+		return err
+	}
+
+	appRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetAppsRequest),
+	)
+
+	appPaginatedRequester, err := api.NewPaginatedRequester(cliConnection, appRequestFactory)
+	if err != nil {
+		return err
+	}
+
+	spaceRequestFactory := apiClient.HandleFiltersAndParameters(
+		apiClient.Authorize(apiClient.NewGetSpacesRequest),
+	)
+
+	spacePaginatedRequester, err := api.NewPaginatedRequester(cliConnection, spaceRequestFactory)
+	if err != nil {
+		return err
+	}
+
+	spaces, err := thingdoer.Spaces(
+		models.SpacesParser{},
+		spacePaginatedRequester,
+	)
+	if err != nil {
+		return err
+	}
+
+	spaceMap := make(map[string]models.Space)
+	for _, space := range spaces {
+		spaceMap[space.Guid] = space
+	}
+
+	filter := api.Filters{}
+	if cmd.OrganizationGuid != "" {
+		filter = append(filter, api.EqualFilter{
+			Name:  "organization_guid",
+			Value: cmd.OrganizationGuid,
+		})
+	}
+
+	apps, err := thingdoer.Apps(
+		models.ApplicationsParser{},
+		appPaginatedRequester,
+		filter,
+	)
+	if err != nil {
+		return err
+	}
+
+	cmd.RuntimeSummaryCommand.AfterAll(Summarize(apps, spaceMap))
+
+	return nil
+}
+
+// Summarize adds up the apps per org, per space and for the whole foundation.
+// Orgs that run entirely on DEA come first, as they are the ones left to
+// migrate; otherwise orgs and the spaces within them are ordered by name.
+func Summarize(apps models.Applications, spaceMap map[string]models.Space) ui.RuntimeSummary {
+	var summary ui.RuntimeSummary
+
+	orgIndex := make(map[string]int)
+	spaceIndex := make(map[string]int)
+
+	for _, app := range apps {
+		space := spaceMap[app.SpaceGuid]
+
+		orgGuid := space.OrganizationGuid
+		if orgGuid == "" {
+			orgGuid = space.Organization.Guid
+		}
+
+		i, ok := orgIndex[orgGuid]
+		if !ok {
+			i = len(summary.Orgs)
+			orgIndex[orgGuid] = i
+			summary.Orgs = append(summary.Orgs, ui.OrgRuntimeUsage{
+				Organization: space.Organization.Name,
+			})
+		}
+
+		j, ok := spaceIndex[app.SpaceGuid]
+		if !ok {
+			j = len(summary.Spaces)
+			spaceIndex[app.SpaceGuid] = j
+			summary.Spaces = append(summary.Spaces, ui.SpaceRuntimeUsage{
+				Organization: space.Organization.Name,
+				Space:        space.Name,
+			})
+		}
+
+		count(&summary.Orgs[i].RuntimeUsage, app)
+		count(&summary.Spaces[j].RuntimeUsage, app)
+		count(&summary.Total, app)
+	}
+
+	sort.Stable(orgsByMigrationProgress(summary.Orgs))
+
+	orgOrder := make(map[string]int)
+	for i, org := range summary.Orgs {
+		orgOrder[org.Organization] = i
+	}
+	sort.Stable(spacesByOrg{spaces: summary.Spaces, orgOrder: orgOrder})
+
+	return summary
+}
+
+func count(usage *ui.RuntimeUsage, app models.Application) {
+	if app.Diego {
+		usage.DiegoApps++
+		usage.DiegoMemory += app.StartedMemory()
+	} else {
+		usage.DEAApps++
+		usage.DEAMemory += app.StartedMemory()
+	}
+}
+
+type orgsByMigrationProgress []ui.OrgRuntimeUsage
+
+func (o orgsByMigrationProgress) Len() int      { return len(o) }
+func (o orgsByMigrationProgress) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
+func (o orgsByMigrationProgress) Less(i, j int) bool {
+	if o[i].AllDEA() != o[j].AllDEA() {
+		return o[i].AllDEA()
+	}
+	return o[i].Organization < o[j].Organization
+}
+
+type spacesByOrg struct {
+	spaces   []ui.SpaceRuntimeUsage
+	orgOrder map[string]int
+}
+
+func (s spacesByOrg) Len() int      { return len(s.spaces) }
+func (s spacesByOrg) Swap(i, j int) { s.spaces[i], s.spaces[j] = s.spaces[j], s.spaces[i] }
+func (s spacesByOrg) Less(i, j int) bool {
+	a, b := s.spaces[i], s.spaces[j]
+	if s.orgOrder[a.Organization] != s.orgOrder[b.Organization] {
+		return s.orgOrder[a.Organization] < s.orgOrder[b.Organization]
+	}
+	return a.Space < b.Space
+}
+
+func NewRuntimeSummaryCommand(cliConnection api.Connection, orgName string) (ui.RuntimeSummaryCommand, error) {
+	username, err := cliConnection.Username()
+	if err != nil {
+		return ui.RuntimeSummaryCommand{}, err
+	}
+
+	traceEnv := os.Getenv("CF_TRACE")
+	traceLogger := trace.NewLogger(false, traceEnv, "")
+	tUI := terminal.NewUI(os.Stdin, terminal.NewTeePrinter(), traceLogger)
+
+	return ui.RuntimeSummaryCommand{
+		Username:     username,
+		Organization: orgName,
+		UI:           tUI,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package summaryhelpers_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/commands/summaryhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Summarize", func() {
+	var (
+		spaceMap map[string]models.Space
+		apps     models.Applications
+	)
+
+	newSpace := func(guid, name, orgGuid, orgName string) models.Space {
+		return models.Space{
+			SpaceEntity: models.SpaceEntity{
+				Name:             name,
+				OrganizationGuid: orgGuid,
+				Organization: models.Organization{
+					OrganizationEntity: models.OrganizationEntity{Name: orgName},
+				},
+			},
+			SpaceMetadata: models.SpaceMetadata{Guid: guid},
+		}
+	}
+
+	newApp := func(spaceGuid string, diego bool, state string, memory int64, instances int) models.Application {
+		return models.Application{
+			ApplicationEntity: models.ApplicationEntity{
+				SpaceGuid: spaceGuid,
+				Diego:     diego,
+				State:     state,
+				Memory:    memory,
+				Instances: instances,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		spaceMap = map[string]models.Space{
+			"migrated-space-guid": newSpace("migrated-space-guid", "migrated-space", "migrated-org-guid", "b-migrated-org"),
+			"dev-space-guid":      newSpace("dev-space-guid", "dev", "legacy-org-guid", "z-legacy-org"),
+			"prod-space-guid":     newSpace("prod-space-guid", "prod", "legacy-org-guid", "z-legacy-org"),
+			"mixed-space-guid":    newSpace("mixed-space-guid", "mixed-space", "mixed-org-guid", "a-mixed-org"),
+		}
+
+		apps = models.Applications{
+			newApp("mixed-space-guid", true, models.Started, 512, 2),
+			newApp("migrated-space-guid", true, models.Started, 1024, 1),
+			newApp("prod-space-guid", false, models.Started, 256, 4),
+			newApp("dev-space-guid", false, models.Stopped, 2048, 1),
+			newApp("mixed-space-guid", false, models.Started, 128, 1),
+		}
+	})
+
+	It("counts apps and the memory of every started instance per runtime", func() {
+		summary := Summarize(apps, spaceMap)
+
+		Expect(summary.Total).To(Equal(ui.RuntimeUsage{
+			DEAApps:     3,
+			DiegoApps:   2,
+			DEAMemory:   256*4 + 128,
+			DiegoMemory: 512*2 + 1024,
+		}))
+	})
+
+	It("lists orgs running entirely on DEA first, then by name", func() {
+		summary := Summarize(apps, spaceMap)
+
+		Expect(summary.Orgs).To(Equal([]ui.OrgRuntimeUsage{
+			{
+				Organization: "z-legacy-org",
+				RuntimeUsage: ui.RuntimeUsage{DEAApps: 2, DEAMemory: 1024},
+			},
+			{
+				Organization: "a-mixed-org",
+				RuntimeUsage: ui.RuntimeUsage{DEAApps: 1, DiegoApps: 1, DEAMemory: 128, DiegoMemory: 1024},
+			},
+			{
+				Organization: "b-migrated-org",
+				RuntimeUsage: ui.RuntimeUsage{DiegoApps: 1, DiegoMemory: 1024},
+			},
+		}))
+	})
+
+	It("lists spaces in the order of their orgs, then by name", func() {
+		summary := Summarize(apps, spaceMap)
+
+		var names []string
+		for _, space := range summary.Spaces {
+			names = append(names, space.Organization+"/"+space.Space)
+		}
+		Expect(names).To(Equal([]string{
+			"z-legacy-org/dev",
+			"z-legacy-org/prod",
+			"a-mixed-org/mixed-space",
+			"b-migrated-org/migrated-space",
+		}))
+	})
+
+	Context("when there are no apps", func() {
+		It("returns an empty summary", func() {
+			summary := Summarize(nil, spaceMap)
+
+			Expect(summary.Orgs).To(BeEmpty())
+			Expect(summary.Spaces).To(BeEmpty())
+			Expect(summary.Total.Apps()).To(Equal(0))
+		})
+	})
+})
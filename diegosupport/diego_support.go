@@ -2,9 +2,10 @@ package diegosupport
 
 import (
 	"encoding/json"
-	"errors"
 	"strconv"
 	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
 )
 
 //go:generate counterfeiter . CliConnection
@@ -16,12 +17,67 @@ type DiegoSupport struct {
 	cli CliConnection
 }
 
-type diegoError struct {
+type DiegoError struct {
 	Code        int64  `json:"code,omitempty"`
 	Description string `json:"description,omitempty"`
 	ErrorCode   string `json:"error_code,omitempty"`
 }
 
+func (e DiegoError) Error() string {
+	return e.ErrorCode + " - " + e.Description
+}
+
+// transientErrorCodes are Cloud Controller errors that describe a passing
+// condition rather than a problem with the request.
+var transientErrorCodes = map[string]bool{
+	"CF-UnknownError":                            true,
+	"CF-ServerError":                             true,
+	"CF-ServiceUnavailable":                      true,
+	"CF-DatabaseError":                           true,
+	"CF-BlobstoreUnavailable":                    true,
+	"CF-RunnerUnavailable":                       true,
+	"CF-StagerUnavailable":                       true,
+	"CF-AsyncServiceInstanceOperationInProgress": true,
+	"CF-ConcurrencyError":                        true,
+	"CF-RateLimitExceeded":                       true,
+}
+
+var transientMessages = []string{
+	"timeout",
+	"connection refused",
+	"connection reset",
+	"eof",
+	"502 bad gateway",
+	"503 service unavailable",
+	"504 gateway timeout",
+	"429 too many requests",
+}
+
+// IsTransient reports whether retrying the request that failed with err may
+// succeed. Responses that are not JSON come from the router or a load
+// balancer in front of the Cloud Controller and are treated as transient.
+func IsTransient(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case DiegoError:
+		return transientErrorCodes[e.ErrorCode]
+	case cloudcontroller.Error:
+		return transientErrorCodes[e.ErrorCode]
+	case *json.SyntaxError:
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, transientMessage := range transientMessages {
+		if strings.Contains(message, transientMessage) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func NewDiegoSupport(cli CliConnection) *DiegoSupport {
 	return &DiegoSupport{
 		cli: cli,
@@ -43,14 +99,14 @@ func (d *DiegoSupport) SetDiegoFlag(appGuid string, enable bool) ([]string, erro
 
 func checkDiegoError(jsonRsp string) error {
 	b := []byte(jsonRsp)
-	diegoErr := diegoError{}
+	diegoErr := DiegoError{}
 	err := json.Unmarshal(b, &diegoErr)
 	if err != nil {
 		return err
 	}
 
 	if diegoErr.ErrorCode != "" || diegoErr.Code != 0 {
-		return errors.New(diegoErr.ErrorCode + " - " + diegoErr.Description)
+		return diegoErr
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package diegosupport_test
 import (
 	"errors"
 
+	"github.com/cloudfoundry-incubator/diego-enabler/cloudcontroller"
 	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport"
 	"github.com/cloudfoundry-incubator/diego-enabler/diegosupport/diegosupportfakes"
 
@@ -93,4 +94,36 @@ var _ = Describe("DiegoSupport", func() {
 			})
 		})
 	})
+
+	Describe("IsTransient", func() {
+		It("classifies Cloud Controller errors by error code", func() {
+			Expect(diegosupport.IsTransient(diegosupport.DiegoError{ErrorCode: "CF-ServiceUnavailable"})).To(BeTrue())
+			Expect(diegosupport.IsTransient(diegosupport.DiegoError{ErrorCode: "CF-AsyncServiceInstanceOperationInProgress"})).To(BeTrue())
+			Expect(diegosupport.IsTransient(diegosupport.DiegoError{ErrorCode: "CF-NotAuthorized"})).To(BeFalse())
+			Expect(diegosupport.IsTransient(diegosupport.DiegoError{ErrorCode: "CF-AppNotFound"})).To(BeFalse())
+		})
+
+		It("classifies errors polling the Cloud Controller by error code", func() {
+			Expect(diegosupport.IsTransient(cloudcontroller.Error{ErrorCode: "CF-ServiceUnavailable"})).To(BeTrue())
+			Expect(diegosupport.IsTransient(cloudcontroller.Error{ErrorCode: "CF-RateLimitExceeded"})).To(BeTrue())
+			Expect(diegosupport.IsTransient(cloudcontroller.Error{ErrorCode: "CF-NotStaged"})).To(BeFalse())
+		})
+
+		It("treats rate limiting as transient", func() {
+			Expect(diegosupport.IsTransient(errors.New("429 Too Many Requests"))).To(BeTrue())
+		})
+
+		It("treats responses that are not JSON as transient", func() {
+			fakeCliConnection.CliCommandWithoutTerminalOutputReturns([]string{"<html>502 Bad Gateway</html>"}, nil)
+
+			_, err := diegoSupport.SetDiegoFlag("test-app-guid", true)
+			Expect(diegosupport.IsTransient(err)).To(BeTrue())
+		})
+
+		It("treats timeouts from curl as transient", func() {
+			Expect(diegosupport.IsTransient(errors.New("Error performing request: net/http: request canceled (Client.Timeout exceeded while awaiting headers)"))).To(BeTrue())
+			Expect(diegosupport.IsTransient(errors.New("dial tcp: i/o timeout"))).To(BeTrue())
+			Expect(diegosupport.IsTransient(errors.New("some other error"))).To(BeFalse())
+		})
+	})
 })
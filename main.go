@@ -25,10 +25,13 @@ func (c *DiegoEnabler) GetMetadata() plugin.PluginMetadata {
 				Name:     "enable-diego",
 				HelpText: "Migrate app to the Diego runtime",
 				UsageDetails: plugin.Usage{
-					Usage: `cf enable-diego APP_NAME
+					Usage: `cf enable-diego APP_NAME [--blue-green]
 
 WARNING:
-   Migration of a running app causes a restart. Stopped apps will be configured to run on the target runtime but are not started.`,
+   Migration of a running app causes a restart unless --blue-green is used. Stopped apps will be configured to run on the target runtime but are not started.
+
+OPTIONS:
+   --blue-green      Start a copy of the app on Diego and move the app's routes to it instead of restarting the app in place`,
 				},
 			},
 			{
@@ -52,37 +55,156 @@ WARNING:
 				Name:     "diego-apps",
 				HelpText: "Lists all apps running on the Diego runtime that are visible to the user",
 				UsageDetails: plugin.Usage{
-					Usage: `cf diego-apps [-o ORG | -s SPACE]
+					Usage: `cf diego-apps [-o ORG | -s SPACE] [--include PATTERN]... [--exclude PATTERN]...
+   [--state (STARTED | STOPPED)] [--buildpack BUILDPACK] [--stack STACK] [--exclude-file FILE]
+   [--columns COLUMNS | --output (json | yaml | csv)]
 
 OPTIONS:
    -o      Organization to restrict the app migration to,
-   -s      Space in the targeted organization to limit results to`,
+   -s      Space in the targeted organization to limit results to
+   --include          Only list apps whose name matches PATTERN, a glob, e.g. api-*, or a regular expression wrapped in slashes, e.g. /^api-/; may be repeated
+   --exclude          Do not list apps whose name matches PATTERN; may be repeated
+   --state            Only list apps in STATE
+   --buildpack        Only list apps whose buildpack, or detected buildpack, matches BUILDPACK, e.g. ruby*
+   --stack            Only list apps on STACK
+   --exclude-file     Do not list the apps whose guids are listed in FILE, one per line
+   --columns          Comma-separated columns of the table: name, space, org, state, instances (running/desired), memory, disk, stack,
+                      buildpack (or docker image), health-check or package-updated (Default: name,space,org)
+   --output           Format of the list: table, or json, yaml or csv for scripts, listing each app's guid, name, org, space, state and runtime (Default: table)`,
 				},
 			},
 			{
 				Name:     "dea-apps",
 				HelpText: "Lists all apps running on the DEA runtime that are visible to the user",
 				UsageDetails: plugin.Usage{
-					Usage: `cf dea-apps [-o ORG | -s SPACE]
+					Usage: `cf dea-apps [-o ORG | -s SPACE] [--include PATTERN]... [--exclude PATTERN]...
+   [--state (STARTED | STOPPED)] [--buildpack BUILDPACK] [--stack STACK] [--exclude-file FILE]
+   [--columns COLUMNS | --output (json | yaml | csv)]
 
 OPTIONS:
    -o      Organization to restrict the app migration to,
-   -s      Space in the targeted organization to limit results to`,
+   -s      Space in the targeted organization to limit results to
+   --include          Only list apps whose name matches PATTERN, a glob, e.g. api-*, or a regular expression wrapped in slashes, e.g. /^api-/; may be repeated
+   --exclude          Do not list apps whose name matches PATTERN; may be repeated
+   --state            Only list apps in STATE
+   --buildpack        Only list apps whose buildpack, or detected buildpack, matches BUILDPACK, e.g. ruby*
+   --stack            Only list apps on STACK
+   --exclude-file     Do not list the apps whose guids are listed in FILE, one per line
+   --columns          Comma-separated columns of the table: name, space, org, state, instances (running/desired), memory, disk, stack,
+                      buildpack (or docker image), health-check or package-updated (Default: name,space,org)
+   --output           Format of the list: table, or json, yaml or csv for scripts, listing each app's guid, name, org, space, state and runtime (Default: table)`,
 				},
 			},
 			{
 				Name:     "migrate-apps",
 				HelpText: "Migrate all apps to Diego/DEA",
 				UsageDetails: plugin.Usage{
-					Usage: `cf migrate-apps (diego | dea) [-o ORG | -s SPACE] [-p MAX_IN_FLIGHT]
+					Usage: `cf migrate-apps (diego | dea) [-o ORG | -s SPACE] [-p MAX_IN_FLIGHT] [--max-memory-in-flight MEMORY]
+   [--include PATTERN]... [--exclude PATTERN]... [--state (STARTED | STOPPED)] [--buildpack BUILDPACK] [--stack STACK] [--exclude-file FILE]
+   [--max-per-space MAX_PER_SPACE] [--max-per-org MAX_PER_ORG] [--rollback-on-failure]
+   [--order-by (name | memory | instances | space | package-updated) [--desc]]
+   [--journal JOURNAL | --resume JOURNAL] [--plan PLAN] [--dry-run [--save-plan PLAN]] [--blue-green]
+   [--report FILE [--report-format (json | csv | junit)]] [--retries RETRIES] [--retry-wait WAIT]
+   [--canary CANARY [--auto-continue-after DURATION]] [--timeout TIMEOUT | --timeout-multiplier MULTIPLIER]
 
 WARNING:
-   Migration of a running app causes a restart. Stopped apps will be configured to run on the target runtime but are not started.
+   Migration of a running app causes a restart unless --blue-green is used. Stopped apps will be configured to run on the target runtime but are not started.
 
 OPTIONS:
    -o      Organization to restrict the app migration to
    -s      Space in the targeted organization to restrict the app migration to
-   -p      Maximum number of apps to migrate in parallel (Default: 1, maximum: 100)`,
+   --include                  Only migrate apps whose name matches PATTERN, a glob, e.g. api-*, or a regular expression wrapped in slashes, e.g. /^api-/; may be repeated
+   --exclude                  Do not migrate apps whose name matches PATTERN; may be repeated
+   --state                    Only migrate apps in STATE
+   --buildpack                Only migrate apps whose buildpack, or detected buildpack, matches BUILDPACK, e.g. ruby*
+   --stack                    Only migrate apps on STACK
+   --exclude-file             Do not migrate the apps whose guids are listed in FILE, one per line
+   -p      Maximum number of apps to migrate in parallel (Default: 1, maximum: 100); started apps sharing a route are migrated one after another
+   --max-memory-in-flight     Maximum memory of the apps migrated in parallel, counting every instance, e.g. 64G; an app larger than MEMORY is migrated on its own
+   --max-per-space            Maximum number of apps of a single space to migrate in parallel; apps of other spaces are migrated meanwhile
+   --max-per-org              Maximum number of apps of a single org to migrate in parallel; apps of other orgs are migrated meanwhile
+   --order-by                 Migrate apps in order of name, memory (counting every instance of started apps, so stopped apps come first), instances, org and space, or time their package was last updated
+   --desc                     With --order-by, migrate apps in descending order
+   --rollback-on-failure      Move apps that fail to start, or run fewer instances than before, on the target runtime back to their original runtime
+   --journal                  Record the progress of the migration in JOURNAL
+   --resume                   Resume the migration recorded in JOURNAL, skipping apps it has already verified
+   --plan                     Migrate the apps listed in PLAN instead of all apps in the org or space
+   --dry-run                  List the apps that would be migrated without migrating them
+   --save-plan                With --dry-run, write the apps that would be migrated to PLAN
+   --blue-green               Start a copy of each started app on the target runtime and move its routes to it instead of restarting the app in place
+   --report                   Write the outcome of migrating each app to FILE
+   --report-format            Format of the report: json, csv or junit (Default: json)
+   --retries                  Number of times to retry a request that fails with a transient Cloud Controller error (Default: 3)
+   --retry-wait               Time to wait before the first retry, e.g. 500ms or 2s; the wait doubles with every retry (Default: 1s)
+   --canary                   Migrate this many apps, e.g. 5, or this percentage of apps, e.g. 10%, first and pause before migrating the rest
+   --auto-continue-after      With --canary, continue without prompting once the canary apps have stayed healthy for DURATION, e.g. 10m
+   --timeout                  Time to wait for each app's instances to start, e.g. 3m (Default: the app's health check timeout plus time to place each instance; CF_STARTUP_TIMEOUT is not used)
+   --timeout-multiplier       Factor to scale the time each app is given to start by, e.g. 1.5 (Default: 1)`,
+				},
+			},
+			{
+				Name:     "diego-readiness",
+				HelpText: "Report known incompatibilities of apps with the Diego runtime",
+				UsageDetails: plugin.Usage{
+					Usage: `cf diego-readiness [APP_NAME | -o ORG | -s SPACE] [--diego-stack STACK]...
+
+Checks the named app, or every app on the DEA runtime in the org or space.
+
+OPTIONS:
+   -o             Organization to restrict the check to
+   -s             Space in the targeted organization to restrict the check to
+   --diego-stack  Stack the Diego cells support, instead of cflinuxfs2, cflinuxfs3, windows2012R2 and windows2016; may be repeated`,
+				},
+			},
+			{
+				Name:     "runtime-summary",
+				HelpText: "Summarize how many apps and how much memory run on DEA and on Diego",
+				UsageDetails: plugin.Usage{
+					Usage: `cf runtime-summary [-o ORG]
+
+Counts apps, and the memory of the instances of started apps, on each runtime
+per org, per space and for the whole foundation. Orgs with every app on DEA
+are listed first.
+
+OPTIONS:
+   -o      Organization to restrict the summary to`,
+				},
+			},
+			{
+				Name:     "runtime-snapshot",
+				HelpText: "Save the runtime of every visible app, compare snapshots and restore them",
+				UsageDetails: plugin.Usage{
+					Usage: `cf runtime-snapshot save FILE
+   cf runtime-snapshot diff OLD [NEW]
+   cf runtime-snapshot restore FILE [--only-changed] [-p MAX_IN_FLIGHT] [--max-memory-in-flight MEMORY]
+      [--max-per-space MAX_PER_SPACE] [--max-per-org MAX_PER_ORG] [--rollback-on-failure] [--dry-run]
+      [--report FILE [--report-format (json | csv | junit)]] [--retries RETRIES] [--retry-wait WAIT]
+      [--timeout TIMEOUT | --timeout-multiplier MULTIPLIER]
+
+save records the guid, name, org, space, state and runtime of every app
+visible to the user in FILE.
+
+diff shows the apps that moved to Diego, moved back to DEA, appeared or
+disappeared between the OLD and NEW snapshots, or between OLD and the apps
+running now when NEW is omitted.
+
+restore moves every app in the snapshot that still exists back to the runtime
+recorded for it, verifying and reporting on each app like migrate-apps.
+
+RESTORE OPTIONS:
+   --only-changed              Only migrate the apps no longer on the runtime recorded for them
+   -p                          Maximum number of apps to migrate in parallel (maximum: 100)
+   --max-memory-in-flight      Maximum memory, summed over the instances of the apps being migrated in parallel, e.g. 64G
+   --max-per-space             Maximum number of apps of a single space to migrate in parallel
+   --max-per-org               Maximum number of apps of a single org to migrate in parallel
+   --rollback-on-failure       Move apps that fail to start, or run fewer instances than before, back to the runtime they were on
+   --dry-run                   List the apps that would be migrated without migrating them
+   --report                    Write the outcome of migrating each app to FILE
+   --report-format             Format of the report: json, csv or junit
+   --retries                   Number of times to retry a request that fails with a transient Cloud Controller error
+   --retry-wait                Time to wait before the first retry; the wait doubles with every retry
+   --timeout                   Time to wait for each app's instances to start (Default: derived from each app's health check timeout; CF_STARTUP_TIMEOUT is not used)
+   --timeout-multiplier        Factor to scale the time derived for each app to start by`,
 				},
 			},
 		},
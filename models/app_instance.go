@@ -0,0 +1,48 @@
+package models
+
+import "encoding/json"
+
+const (
+	InstanceRunning  = "RUNNING"
+	InstanceStarting = "STARTING"
+	InstanceCrashed  = "CRASHED"
+	InstanceFlapping = "FLAPPING"
+	InstanceDown     = "DOWN"
+)
+
+type AppInstance struct {
+	State string `json:"state"`
+}
+
+type AppInstances map[string]AppInstance
+
+func (instances AppInstances) Running() int {
+	return instances.count(InstanceRunning)
+}
+
+func (instances AppInstances) Crashed() int {
+	return instances.count(InstanceCrashed) + instances.count(InstanceFlapping)
+}
+
+func (instances AppInstances) count(state string) int {
+	var n int
+	for _, instance := range instances {
+		if instance.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+type AppInstancesParser struct{}
+
+func (p AppInstancesParser) Parse(body []byte) (AppInstances, error) {
+	var instances AppInstances
+
+	err := json.Unmarshal(body, &instances)
+	if err != nil {
+		return AppInstances{}, err
+	}
+
+	return instances, nil
+}
@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppInstances", func() {
+	Describe("Parser", func() {
+		jsonBody := `{
+   "0": {
+      "state": "RUNNING",
+      "since": 1458253620.0
+   },
+   "1": {
+      "state": "CRASHED",
+      "since": 1458253625.0
+   },
+   "2": {
+      "state": "FLAPPING",
+      "since": 1458253630.0
+   },
+   "3": {
+      "state": "STARTING",
+      "since": 1458253635.0
+   }
+}`
+
+		It("parses", func() {
+			instances, err := AppInstancesParser{}.Parse([]byte(jsonBody))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(HaveLen(4))
+			Expect(instances["0"].State).To(Equal(InstanceRunning))
+			Expect(instances["3"].State).To(Equal(InstanceStarting))
+		})
+
+		It("counts running and crashed instances", func() {
+			instances, err := AppInstancesParser{}.Parse([]byte(jsonBody))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances.Running()).To(Equal(1))
+			Expect(instances.Crashed()).To(Equal(2))
+		})
+	})
+})
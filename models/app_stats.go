@@ -0,0 +1,55 @@
+package models
+
+import "encoding/json"
+
+// AppStats maps instance indexes to the resource usage the runtime reports
+// for them. Only running instances report usage.
+type AppStats map[string]AppInstanceStats
+
+type AppInstanceStats struct {
+	State string        `json:"state"`
+	Stats InstanceStats `json:"stats"`
+}
+
+type InstanceStats struct {
+	Usage     InstanceUsage `json:"usage"`
+	DiskQuota int64         `json:"disk_quota"` // in bytes
+	MemQuota  int64         `json:"mem_quota"`  // in bytes
+}
+
+type InstanceUsage struct {
+	Disk int64 `json:"disk"` // in bytes
+	Mem  int64 `json:"mem"`  // in bytes
+}
+
+// MaxDiskUsage returns the highest fraction of its disk quota any instance
+// is using.
+func (s AppStats) MaxDiskUsage() float64 {
+	var max float64
+
+	for _, instance := range s {
+		if instance.Stats.DiskQuota == 0 {
+			continue
+		}
+
+		usage := float64(instance.Stats.Usage.Disk) / float64(instance.Stats.DiskQuota)
+		if usage > max {
+			max = usage
+		}
+	}
+
+	return max
+}
+
+type AppStatsParser struct{}
+
+func (a AppStatsParser) Parse(body []byte) (AppStats, error) {
+	var stats AppStats
+
+	err := json.Unmarshal(body, &stats)
+	if err != nil {
+		return AppStats{}, err
+	}
+
+	return stats, nil
+}
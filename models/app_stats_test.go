@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	. "github.com/cloudfoundry-incubator/diego-enabler/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppStats", func() {
+	Describe("Parser", func() {
+		jsonBody := `{
+   "0": {
+      "state": "RUNNING",
+      "stats": {
+         "usage": {"disk": 512, "mem": 100},
+         "disk_quota": 1024,
+         "mem_quota": 200
+      }
+   },
+   "1": {
+      "state": "RUNNING",
+      "stats": {
+         "usage": {"disk": 921, "mem": 100},
+         "disk_quota": 1024,
+         "mem_quota": 200
+      }
+   },
+   "2": {
+      "state": "DOWN"
+   }
+}`
+
+		It("parses", func() {
+			stats, err := AppStatsParser{}.Parse([]byte(jsonBody))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats).To(HaveLen(3))
+			Expect(stats["0"].Stats.Usage.Disk).To(Equal(int64(512)))
+			Expect(stats["0"].Stats.DiskQuota).To(Equal(int64(1024)))
+		})
+
+		It("reports the highest disk usage across instances", func() {
+			stats, err := AppStatsParser{}.Parse([]byte(jsonBody))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.MaxDiskUsage()).To(BeNumerically("~", 0.9, 0.001))
+		})
+	})
+})
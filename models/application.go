@@ -1,6 +1,9 @@
 package models
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Applications []Application
 
@@ -13,30 +16,54 @@ const (
 	Stopped = "STOPPED"
 )
 
+// UnknownRunningInstances is the RunningInstances of an app whose instances
+// could not be counted.
+const UnknownRunningInstances = -1
+
+const (
+	PackagePending = "PENDING"
+	PackageStaged  = "STAGED"
+	PackageFailed  = "FAILED"
+)
+
 type ApplicationEntity struct {
-	Name string `json:"name"`
-	//BuildpackUrl         string
-	//Command              string
-	Diego bool
-	//DetectedStartCommand string
-	//DiskQuota            int64 // in Megabytes
-	//EnvironmentVars      map[string]interface{}
-	//InstanceCount        int
-	//Memory               int64 // in Megabytes
-	//RunningInstances     int
-	//HealthCheckTimeout   int
-	State     string `json:"state"`
-	SpaceGuid string `json:"space_guid"`
-	//PackageUpdatedAt     *time.Time
-	//PackageState         string
-	//StagingFailedReason  string
-	//AppPorts             []int
+	Name                 string `json:"name"`
+	Buildpack            string `json:"buildpack"`
+	DetectedBuildpack    string `json:"detected_buildpack"`
+	Command              string `json:"command"`
+	Diego                bool
+	DetectedStartCommand string                 `json:"detected_start_command"`
+	DiskQuota            int64                  `json:"disk_quota"` // in Megabytes
+	EnvironmentJson      map[string]interface{} `json:"environment_json"`
+	Instances            int                    `json:"instances"`
+	Memory               int64                  `json:"memory"` // in Megabytes
+	// RunningInstances is only reported by the app summary; listings fill it
+	// in from the app's instances, or with UnknownRunningInstances when they
+	// cannot be counted.
+	RunningInstances         int                `json:"running_instances"`
+	HealthCheckType          string             `json:"health_check_type"`
+	HealthCheckTimeout       int                `json:"health_check_timeout"`
+	State                    string             `json:"state"`
+	SpaceGuid                string             `json:"space_guid"`
+	StackGuid                string             `json:"stack_guid"`
+	DockerImage              string             `json:"docker_image"`
+	PackageUpdatedAt         *time.Time         `json:"package_updated_at"`
+	PackageState             string             `json:"package_state"`
+	StagingFailedReason      string             `json:"staging_failed_reason"`
+	StagingFailedDescription string             `json:"staging_failed_description"`
+	Ports                    []int              `json:"ports"`
+	HealthCheckHTTPEndpoint  string             `json:"health_check_http_endpoint"`
+	EnableSSH                bool               `json:"enable_ssh"`
+	DockerCredentials        *DockerCredentials `json:"docker_credentials"`
 	//Stack                *GetApp_Stack
-	//Instances            []GetApp_AppInstanceFields
-	//Routes               []GetApp_RouteSummary
 	//Services             []GetApp_ServiceSummary
 }
 
+type DockerCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 type ApplicationsResponse struct {
 	Resources Applications `json:"resources"`
 }
@@ -46,6 +73,15 @@ type Application struct {
 	ApplicationMetadata `json:"metadata"`
 }
 
+// StartedMemory is the memory the app's instances take up when it is started.
+// Stopped apps take up none.
+func (a Application) StartedMemory() int64 {
+	if a.State != Started {
+		return 0
+	}
+	return a.Memory * int64(a.Instances)
+}
+
 type ApplicationsParser struct{}
 
 func (a ApplicationsParser) Parse(body []byte) (Applications, error) {
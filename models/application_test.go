@@ -119,6 +119,69 @@ var _ = Describe("Application", func() {
 			Expect(applications[0].SpaceGuid).To(Equal("1f7ac3a5-6f4e-4d6c-8edd-ce694fc8c907"))
 			Expect(applications[0].Guid).To(Equal("b2ba6466-23f7-4f90-935b-4da1c87b8943"))
 			Expect(applications[0].State).To(Equal(Started))
+			Expect(applications[1].Instances).To(Equal(10))
+			Expect(applications[1].PackageState).To(Equal(PackageStaged))
+		})
+
+		It("parses why an app failed to stage", func() {
+			failedBody := `{
+   "total_results": 1,
+   "total_pages": 1,
+   "prev_url": null,
+   "next_url": null,
+   "resources": [
+      {
+         "metadata": {
+            "guid": "7d1f4c2e-3b0a-4f4e-9d5c-6a0b8e2f1c33",
+            "url": "/v2/apps/7d1f4c2e-3b0a-4f4e-9d5c-6a0b8e2f1c33",
+            "created_at": "2016-03-18T10:12:03Z",
+            "updated_at": "2016-03-18T10:13:41Z"
+         },
+         "entity": {
+            "name": "undetectable",
+            "space_guid": "1f7ac3a5-6f4e-4d6c-8edd-ce694fc8c907",
+            "stack_guid": "f3cecf19-4567-4dca-ad35-2a3af733cbde",
+            "buildpack": null,
+            "detected_buildpack": null,
+            "memory": 256,
+            "instances": 1,
+            "disk_quota": 1024,
+            "state": "STARTED",
+            "package_state": "FAILED",
+            "health_check_type": "port",
+            "health_check_timeout": null,
+            "staging_failed_reason": "NoAppDetectedError",
+            "staging_failed_description": "An app was not successfully detected by any available buildpack",
+            "diego": true,
+            "docker_image": null,
+            "package_updated_at": "2016-03-18T10:12:09Z"
+         }
+      }
+   ]
+}`
+
+			applications, err := ApplicationsParser{}.Parse([]byte(failedBody))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applications).To(HaveLen(1))
+			Expect(applications[0].PackageState).To(Equal(PackageFailed))
+			Expect(applications[0].StagingFailedReason).To(Equal("NoAppDetectedError"))
+			Expect(applications[0].StagingFailedDescription).To(Equal("An app was not successfully detected by any available buildpack"))
+		})
+	})
+
+	Describe("StartedMemory", func() {
+		It("counts every instance of a started app", func() {
+			app := Application{
+				ApplicationEntity: ApplicationEntity{State: Started, Memory: 256, Instances: 3},
+			}
+			Expect(app.StartedMemory()).To(Equal(int64(768)))
+		})
+
+		It("does not count stopped apps", func() {
+			app := Application{
+				ApplicationEntity: ApplicationEntity{State: Stopped, Memory: 256, Instances: 3},
+			}
+			Expect(app.StartedMemory()).To(BeZero())
 		})
 	})
 })
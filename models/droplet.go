@@ -0,0 +1,14 @@
+package models
+
+const (
+	DropletCopying = "COPYING"
+	DropletStaged  = "STAGED"
+	DropletFailed  = "FAILED"
+)
+
+// Droplet is a v3 droplet. Droplets are only exposed by the v3 API, which
+// does not wrap resources in metadata and entity.
+type Droplet struct {
+	Guid  string `json:"guid"`
+	State string `json:"state"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "encoding/json"
+
+type Routes []Route
+
+type RouteEntity struct {
+	Host       string `json:"host"`
+	Path       string `json:"path"`
+	DomainGuid string `json:"domain_guid"`
+	SpaceGuid  string `json:"space_guid"`
+}
+
+type RouteMetadata struct {
+	Guid string `json:"guid"`
+}
+
+type RoutesResponse struct {
+	Resources Routes `json:"resources"`
+}
+
+type Route struct {
+	RouteEntity   `json:"entity"`
+	RouteMetadata `json:"metadata"`
+}
+
+type RoutesParser struct{}
+
+func (r RoutesParser) Parse(body []byte) (Routes, error) {
+	var response RoutesResponse
+	var emptyRoutes Routes
+
+	err := json.Unmarshal(body, &response)
+	if err != nil {
+		return emptyRoutes, err
+	}
+
+	return response.Resources, nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import "encoding/json"
+
+type ServiceBindings []ServiceBinding
+
+type ServiceBindingEntity struct {
+	AppGuid             string `json:"app_guid"`
+	ServiceInstanceGuid string `json:"service_instance_guid"`
+}
+
+type ServiceBindingMetadata struct {
+	Guid string `json:"guid"`
+}
+
+type ServiceBindingsResponse struct {
+	Resources ServiceBindings `json:"resources"`
+}
+
+type ServiceBinding struct {
+	ServiceBindingEntity   `json:"entity"`
+	ServiceBindingMetadata `json:"metadata"`
+}
+
+type ServiceBindingsParser struct{}
+
+func (s ServiceBindingsParser) Parse(body []byte) (ServiceBindings, error) {
+	var response ServiceBindingsResponse
+	var emptyServiceBindings ServiceBindings
+
+	err := json.Unmarshal(body, &response)
+	if err != nil {
+		return emptyServiceBindings, err
+	}
+
+	return response.Resources, nil
+}
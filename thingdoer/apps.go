@@ -0,0 +1,30 @@
+package thingdoer
+
+import (
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+func Apps(appsParser ApplicationsParser, paginatedRequester PaginatedRequester, filter api.Filter) (models.Applications, error) {
+	var noApps models.Applications
+
+	params := map[string]interface{}{}
+
+	responseBodies, err := paginatedRequester.Do(filter, params)
+	if err != nil {
+		return noApps, err
+	}
+
+	var applications models.Applications
+
+	for _, nextBody := range responseBodies {
+		apps, err := appsParser.Parse(nextBody)
+		if err != nil {
+			return noApps, err
+		}
+
+		applications = append(applications, apps...)
+	}
+
+	return applications, nil
+}
@@ -0,0 +1,93 @@
+package thingdoer_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer"
+	"github.com/cloudfoundry-incubator/diego-enabler/thingdoer/thingdoerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Apps", func() {
+	var (
+		fakePaginatedRequester *thingdoerfakes.FakePaginatedRequester
+		fakeApplicationsParser *thingdoerfakes.FakeApplicationsParser
+		filter                 api.Filters
+		apps                   models.Applications
+		err                    error
+	)
+
+	BeforeEach(func() {
+		fakePaginatedRequester = new(thingdoerfakes.FakePaginatedRequester)
+		fakeApplicationsParser = new(thingdoerfakes.FakeApplicationsParser)
+		filter = api.Filters{
+			api.EqualFilter{
+				Name:  "name",
+				Value: "some-app",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		apps, err = thingdoer.Apps(fakeApplicationsParser, fakePaginatedRequester, filter)
+	})
+
+	It("should create a request with the given filter", func() {
+		Expect(fakePaginatedRequester.DoCallCount()).To(Equal(1))
+		requestFilter, _ := fakePaginatedRequester.DoArgsForCall(0)
+		Expect(requestFilter).To(Equal(filter))
+	})
+
+	Context("when the paginated requester fails", func() {
+		var requestError error
+
+		BeforeEach(func() {
+			requestError = errors.New("making API requests failed")
+			fakePaginatedRequester.DoReturns([][]byte{}, requestError)
+		})
+
+		It("returns the requester error", func() {
+			Expect(apps).To(BeEmpty())
+			Expect(err).To(Equal(requestError))
+		})
+	})
+
+	Context("when the paginated requester succeeds", func() {
+		BeforeEach(func() {
+			fakePaginatedRequester.DoReturns([][]byte{
+				[]byte("some-json"),
+				[]byte("some-other-json"),
+			}, nil)
+			fakeApplicationsParser.ParseStub = func(body []byte) (models.Applications, error) {
+				return models.Applications{
+					{ApplicationEntity: models.ApplicationEntity{Name: string(body)}},
+				}, nil
+			}
+		})
+
+		It("returns the apps from every page", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apps).To(HaveLen(2))
+			Expect(apps[0].Name).To(Equal("some-json"))
+			Expect(apps[1].Name).To(Equal("some-other-json"))
+		})
+	})
+
+	Context("when parsing fails", func() {
+		var parseError error
+
+		BeforeEach(func() {
+			parseError = errors.New("parsing failed")
+			fakePaginatedRequester.DoReturns([][]byte{[]byte("some-json")}, nil)
+			fakeApplicationsParser.ParseReturns(nil, parseError)
+		})
+
+		It("returns the parser error", func() {
+			Expect(apps).To(BeEmpty())
+			Expect(err).To(Equal(parseError))
+		})
+	})
+})
@@ -33,6 +33,8 @@ func (c AppsGetter) DeaApps(appsParser ApplicationsParser, paginatedRequester Pa
 		)
 	}
 
+	filter = append(filter, c.Filters...)
+
 	params := map[string]interface{}{}
 
 	responseBodies, err := paginatedRequester.Do(filter, params)
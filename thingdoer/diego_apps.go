@@ -15,6 +15,8 @@ type ApplicationsParser interface {
 type AppsGetter struct {
 	OrganizationGuid string
 	SpaceGuid        string
+	// Filters narrow the apps further, e.g. by name or stack.
+	Filters api.Filters
 }
 
 func (c AppsGetter) DiegoApps(
@@ -48,6 +50,8 @@ func (c AppsGetter) DiegoApps(
 		)
 	}
 
+	filter = append(filter, c.Filters...)
+
 	params := map[string]interface{}{}
 
 	responseBodies, err := paginatedRequester.Do(filter, params)
@@ -90,6 +90,39 @@ var _ = Describe("DiegoApps", func() {
 		})
 	})
 
+	Context("when additional filters are specified", func() {
+		BeforeEach(func() {
+			command.SpaceGuid = "some-space-guid"
+			command.Filters = api.Filters{
+				api.EqualFilter{
+					Name:  "stack_guid",
+					Value: "some-stack-guid",
+				},
+			}
+		})
+
+		It("should create a request with the additional filters", func() {
+			expectedFilters := api.Filters{
+				api.EqualFilter{
+					Name:  "diego",
+					Value: true,
+				},
+				api.EqualFilter{
+					Name:  "space_guid",
+					Value: "some-space-guid",
+				},
+				api.EqualFilter{
+					Name:  "stack_guid",
+					Value: "some-stack-guid",
+				},
+			}
+
+			Expect(fakePaginatedRequester.DoCallCount()).To(Equal(1))
+			filters, _ := fakePaginatedRequester.DoArgsForCall(0)
+			Expect(filters).To(Equal(expectedFilters))
+		})
+	})
+
 	Context("when the paginated requester fails", func() {
 		var requestError error
 
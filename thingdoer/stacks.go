@@ -0,0 +1,37 @@
+package thingdoer
+
+import (
+	"github.com/cloudfoundry-incubator/diego-enabler/api"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+)
+
+//go:generate counterfeiter . StacksParser
+type StacksParser interface {
+	Parse([]byte) (models.Stacks, error)
+}
+
+func Stacks(stacksParser StacksParser, paginatedRequester PaginatedRequester) (models.Stacks, error) {
+	var noStacks models.Stacks
+
+	filter := api.Filters{}
+
+	params := map[string]interface{}{}
+
+	responseBodies, err := paginatedRequester.Do(filter, params)
+	if err != nil {
+		return noStacks, err
+	}
+
+	var stacks models.Stacks
+
+	for _, nextBody := range responseBodies {
+		page, err := stacksParser.Parse(nextBody)
+		if err != nil {
+			return noStacks, err
+		}
+
+		stacks = append(stacks, page...)
+	}
+
+	return stacks, nil
+}
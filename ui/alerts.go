@@ -4,7 +4,7 @@ import "github.com/fatih/color"
 
 func SayOK() {
 	c := color.New(color.FgGreen).Add(color.Bold)
-	c.Println("OK\n")
+	c.Print("OK\n\n")
 }
 
 func SayFailed() {
@@ -2,16 +2,115 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/terminal"
 )
 
+type Column string
+
+const (
+	ColumnName           Column = "name"
+	ColumnSpace          Column = "space"
+	ColumnOrg            Column = "org"
+	ColumnState          Column = "state"
+	ColumnInstances      Column = "instances"
+	ColumnMemory         Column = "memory"
+	ColumnDisk           Column = "disk"
+	ColumnStack          Column = "stack"
+	ColumnBuildpack      Column = "buildpack"
+	ColumnHealthCheck    Column = "health-check"
+	ColumnPackageUpdated Column = "package-updated"
+)
+
+var AllColumns = []Column{
+	ColumnName,
+	ColumnSpace,
+	ColumnOrg,
+	ColumnState,
+	ColumnInstances,
+	ColumnMemory,
+	ColumnDisk,
+	ColumnStack,
+	ColumnBuildpack,
+	ColumnHealthCheck,
+	ColumnPackageUpdated,
+}
+
+var DefaultColumns = []Column{
+	ColumnName,
+	ColumnSpace,
+	ColumnOrg,
+}
+
+type InvalidColumnError struct {
+	Column string
+}
+
+func (e InvalidColumnError) Error() string {
+	var names []string
+	for _, column := range AllColumns {
+		names = append(names, string(column))
+	}
+
+	return fmt.Sprintf("Invalid column: %s\nColumns must be a comma-separated list of %s", e.Column, strings.Join(names, ", "))
+}
+
+// ParseColumns parses a comma-separated list of columns. Without any, the
+// default columns are listed.
+func ParseColumns(value string) ([]Column, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultColumns, nil
+	}
+
+	var columns []Column
+	for _, name := range strings.Split(value, ",") {
+		column := Column(strings.ToLower(strings.TrimSpace(name)))
+		if !knownColumn(column) {
+			return nil, InvalidColumnError{Column: name}
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func knownColumn(column Column) bool {
+	for _, known := range AllColumns {
+		if column == known {
+			return true
+		}
+	}
+	return false
+}
+
 type ListAppsCommand struct {
 	Username     string
 	Runtime      Runtime
 	Organization string
 	Space        string
 	UI           terminal.UI
+	// Columns are listed in order; DefaultColumns when empty.
+	Columns []Column
+}
+
+// Shows reports whether the column is listed.
+func (c *ListAppsCommand) Shows(column Column) bool {
+	for _, listed := range c.columns() {
+		if listed == column {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ListAppsCommand) columns() []Column {
+	if len(c.Columns) == 0 {
+		return DefaultColumns
+	}
+	return c.Columns
 }
 
 func (c *ListAppsCommand) BeforeAll() {
@@ -43,16 +142,63 @@ func (c *ListAppsCommand) BeforeAll() {
 func (c *ListAppsCommand) AfterAll(apps []ApplicationPrinter) {
 	SayOK()
 
-	headers := []string{
-		"name",
-		"space",
-		"org",
+	var headers []string
+	for _, column := range c.columns() {
+		headers = append(headers, string(column))
 	}
 	t := terminal.NewTable(c.UI, headers)
 
 	for _, app := range apps {
-		t.Add(app.Name(), app.Space(), app.Organization())
+		var row []string
+		for _, column := range c.columns() {
+			row = append(row, cell(app, column))
+		}
+		t.Add(row...)
 	}
 
 	t.Print()
 }
+
+func cell(app ApplicationPrinter, column Column) string {
+	switch column {
+	case ColumnName:
+		return app.Name()
+	case ColumnSpace:
+		return app.Space()
+	case ColumnOrg:
+		return app.Organization()
+	case ColumnState:
+		return app.State()
+	case ColumnInstances:
+		if app.RunningInstances() < 0 {
+			return fmt.Sprintf("?/%d", app.DesiredInstances())
+		}
+		return fmt.Sprintf("%d/%d", app.RunningInstances(), app.DesiredInstances())
+	case ColumnMemory:
+		return formatMegabytes(app.Memory())
+	case ColumnDisk:
+		return formatMegabytes(app.DiskQuota())
+	case ColumnStack:
+		return app.Stack()
+	case ColumnBuildpack:
+		return app.Buildpack()
+	case ColumnHealthCheck:
+		return app.HealthCheckType()
+	case ColumnPackageUpdated:
+		if updated := app.PackageUpdatedAt(); updated != nil {
+			return updated.UTC().Format(time.RFC3339)
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// formatMegabytes formats an amount of memory as `cf app` does, e.g. 512M or
+// 1G.
+func formatMegabytes(megabytes int64) string {
+	if megabytes >= 1024 && megabytes%1024 == 0 {
+		return strconv.FormatInt(megabytes/1024, 10) + "G"
+	}
+	return strconv.FormatInt(megabytes, 10) + "M"
+}
@@ -2,6 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/cloudfoundry/cli/cf/terminal"
 )
@@ -11,10 +14,32 @@ type MigrateAppsCommand struct {
 	Runtime      Runtime
 	Organization string
 	Space        string
+	// Snapshot is the runtime snapshot being restored, in which case each
+	// app moves to the runtime recorded for it rather than to Runtime.
+	Snapshot string
+	UI       terminal.UI
+	// Progress shows the apps being migrated. Without it, messages are
+	// printed as they come.
+	Progress Progress
+}
+
+func (c *MigrateAppsCommand) say(app ApplicationPrinter, format string, a ...interface{}) {
+	if c.Progress == nil {
+		fmt.Printf(format, a...)
+		return
+	}
+
+	c.Progress.Say(app, fmt.Sprintf(format, a...))
 }
 
 func (c *MigrateAppsCommand) BeforeAll() {
 	switch {
+	case c.Snapshot != "":
+		fmt.Printf(
+			"Restoring the runtimes recorded in snapshot %s as %s...\n",
+			terminal.EntityNameColor(c.Snapshot),
+			terminal.EntityNameColor(c.Username),
+		)
 	case c.Organization != "" && c.Space != "":
 		fmt.Printf(
 			"Migrating apps to %s in org %s / %s as %s...\n",
@@ -40,9 +65,13 @@ func (c *MigrateAppsCommand) BeforeAll() {
 }
 
 func (c *MigrateAppsCommand) BeforeEach(app ApplicationPrinter) {
-	fmt.Println()
-	fmt.Printf(
-		"Migrating app %s in org %s / space %s to %s as %s...\n",
+	if c.Progress != nil {
+		c.Progress.Start(app)
+	}
+
+	c.say(
+		app,
+		"\nMigrating app %s in org %s / space %s to %s as %s...\n",
 		terminal.EntityNameColor(app.Name()),
 		terminal.EntityNameColor(app.Organization()),
 		terminal.EntityNameColor(app.Space()),
@@ -52,9 +81,9 @@ func (c *MigrateAppsCommand) BeforeEach(app ApplicationPrinter) {
 }
 
 func (c *MigrateAppsCommand) CompletedEach(app ApplicationPrinter) {
-	fmt.Println()
-	fmt.Printf(
-		"Completed migrating app %s in org %s / space %s to %s as %s\n",
+	c.say(
+		app,
+		"\nCompleted migrating app %s in org %s / space %s to %s as %s\n",
 		terminal.EntityNameColor(app.Name()),
 		terminal.EntityNameColor(app.Organization()),
 		terminal.EntityNameColor(app.Space()),
@@ -64,17 +93,233 @@ func (c *MigrateAppsCommand) CompletedEach(app ApplicationPrinter) {
 }
 
 func (c *MigrateAppsCommand) DuringEach(app ApplicationPrinter) {
-	fmt.Print(".")
+	if c.Progress == nil {
+		fmt.Print(".")
+	}
+}
+
+func (c *MigrateAppsCommand) PhaseEach(app ApplicationPrinter, phase string) {
+	if c.Progress != nil {
+		c.Progress.Phase(app, phase)
+	}
+}
+
+func (c *MigrateAppsCommand) InstancesEach(app ApplicationPrinter, running int, desired int) {
+	if c.Progress != nil {
+		c.Progress.Instances(app, running, desired)
+	}
+}
+
+func (c *MigrateAppsCommand) AfterEach(app ApplicationPrinter, failed bool) {
+	if c.Progress != nil {
+		c.Progress.Finish(app, failed)
+	}
+}
+
+type MigrationSummary struct {
+	Attempts       int
+	Warnings       int
+	Errors         int
+	RolledBack     []ApplicationPrinter
+	RollbackFailed []ApplicationPrinter
+	Degraded       []ApplicationPrinter
+	Interrupted    bool
+	NotAttempted   int
+	Retried        []AppAttempts
+}
+
+type AppAttempts struct {
+	App      ApplicationPrinter
+	Attempts int
 }
 
-func (c *MigrateAppsCommand) AfterAll(attempts, warnings int, errors int) {
-	successes := attempts - warnings - errors
+func (c *MigrateAppsCommand) AfterAll(summary MigrationSummary) {
+	rolledBack := len(summary.RolledBack)
+	rollbackFailed := len(summary.RollbackFailed)
+	degraded := len(summary.Degraded)
+	successes := summary.Attempts - summary.Warnings - summary.Errors - rolledBack - rollbackFailed - degraded
+
+	status := "completed"
+	if summary.Interrupted {
+		status = "interrupted"
+	}
+
+	counts := fmt.Sprintf("%d apps, %d errors, %d warnings", successes, summary.Errors, summary.Warnings)
+	if degraded > 0 {
+		counts += fmt.Sprintf(", %d degraded", degraded)
+	}
+
 	fmt.Println()
-	fmt.Printf("Migration to %s completed: %d apps, %d errors, %d warnings\n", terminal.EntityNameColor(c.Runtime.String()), successes, errors, warnings)
+	if rolledBack == 0 && rollbackFailed == 0 {
+		fmt.Printf("%s %s: %s\n", c.title(), status, counts)
+		c.printDegraded(summary)
+		c.printRetried(summary)
+		c.printNotAttempted(summary)
+		return
+	}
+
+	fmt.Printf(
+		"%s %s: %s, %d rolled back, %d failed to roll back\n",
+		c.title(),
+		status,
+		counts,
+		rolledBack,
+		rollbackFailed,
+	)
+
+	c.printDegraded(summary)
+
+	if rolledBack > 0 {
+		if c.Snapshot != "" {
+			fmt.Println("\nApps rolled back to their previous runtime:")
+		} else {
+			fmt.Printf("\nApps rolled back to %s:\n", terminal.EntityNameColor(c.Runtime.Flip().String()))
+		}
+		printAppList(summary.RolledBack)
+	}
+
+	if rollbackFailed > 0 {
+		fmt.Printf("\n%s\n", terminal.FailureColor("Apps that failed to roll back and need attention:"))
+		printAppList(summary.RollbackFailed)
+	}
+
+	c.printRetried(summary)
+	c.printNotAttempted(summary)
 }
 
-func (c *MigrateAppsCommand) UserWarning(app ApplicationPrinter) {
+func (c *MigrateAppsCommand) title() string {
+	if c.Snapshot != "" {
+		return fmt.Sprintf("Restore of snapshot %s", terminal.EntityNameColor(c.Snapshot))
+	}
+	return fmt.Sprintf("Migration to %s", terminal.EntityNameColor(c.Runtime.String()))
+}
+
+func (c *MigrateAppsCommand) printDegraded(summary MigrationSummary) {
+	if len(summary.Degraded) > 0 {
+		fmt.Printf("\n%s\n", terminal.FailureColor("Apps running fewer instances than before the migration:"))
+		printAppList(summary.Degraded)
+	}
+}
+
+func (c *MigrateAppsCommand) printRetried(summary MigrationSummary) {
+	if len(summary.Retried) == 0 {
+		return
+	}
+
+	fmt.Println("\nApps that needed more than one attempt:")
+	for _, retried := range summary.Retried {
+		fmt.Printf(
+			"   %s in org %s / space %s: %d attempts\n",
+			terminal.EntityNameColor(retried.App.Name()),
+			terminal.EntityNameColor(retried.App.Organization()),
+			terminal.EntityNameColor(retried.App.Space()),
+			retried.Attempts,
+		)
+	}
+}
+
+func (c *MigrateAppsCommand) printNotAttempted(summary MigrationSummary) {
+	if summary.NotAttempted > 0 {
+		fmt.Printf("%d apps were not migrated\n", summary.NotAttempted)
+	}
+}
+
+func (c *MigrateAppsCommand) Interrupted() {
+	c.say(
+		nil,
+		"\n%s\n",
+		terminal.WarningColor("Interrupted: waiting for apps being migrated to finish. Press Ctrl-C again to exit immediately."),
+	)
+}
+
+func (c *MigrateAppsCommand) BeforeCanaries(canaries int) {
+	c.say(nil, "Migrating %d canary apps first...\n", canaries)
+}
+
+func (c *MigrateAppsCommand) AfterCanaries(summary MigrationSummary, remaining int) {
+	failed := summary.Errors + len(summary.RolledBack) + len(summary.RollbackFailed) + len(summary.Degraded)
+
+	c.say(
+		nil,
+		"\nCanary migration to %s completed: %d apps, %d failed, %d warnings; %d apps remaining\n",
+		terminal.EntityNameColor(c.Runtime.String()),
+		summary.Attempts-failed-summary.Warnings,
+		failed,
+		summary.Warnings,
+		remaining,
+	)
+}
+
+func (c *MigrateAppsCommand) ConfirmContinue(remaining int) bool {
+	if c.Progress != nil {
+		c.Progress.Pause()
+	}
+	return c.UI.Confirm(fmt.Sprintf("Migrate the remaining %d apps to %s?", remaining, c.Runtime.String()))
+}
+
+func (c *MigrateAppsCommand) WatchCanaries(duration time.Duration) {
+	c.say(nil, "Watching the canary apps for %s before migrating the remaining apps...\n", duration)
+}
+
+func (c *MigrateAppsCommand) HoldRouteGroup(app ApplicationPrinter, held []ApplicationPrinter) {
+	c.say(
+		app,
+		"%s\n",
+		terminal.WarningColor(fmt.Sprintf("Not migrating %d apps sharing a route with app %s, so that they keep serving its routes:", len(held), app.Name())),
+	)
+	for _, heldApp := range held {
+		c.say(
+			app,
+			"   %s in org %s / space %s\n",
+			terminal.EntityNameColor(heldApp.Name()),
+			terminal.EntityNameColor(heldApp.Organization()),
+			terminal.EntityNameColor(heldApp.Space()),
+		)
+	}
+}
+
+func (c *MigrateAppsCommand) RoutesWarning(app ApplicationPrinter, err error) {
 	fmt.Printf(
+		"%s\n",
+		terminal.WarningColor(fmt.Sprintf(
+			"Could not get the routes of app %s in org %s / space %s, so it is migrated regardless of the apps it may share a route with: %s",
+			app.Name(),
+			app.Organization(),
+			app.Space(),
+			err,
+		)),
+	)
+}
+
+func (c *MigrateAppsCommand) ForceExit() {
+	c.say(nil, "\n%s\n", terminal.FailureColor("Exiting without waiting for apps being migrated"))
+}
+
+func (c *MigrateAppsCommand) AbandonedEach(app ApplicationPrinter) {
+	c.say(
+		app,
+		"\nError: Stopped waiting for app %s in space %s / org %s to start on %s after the interrupt\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(c.Runtime.String()),
+	)
+}
+
+func printAppList(apps []ApplicationPrinter) {
+	for _, app := range apps {
+		fmt.Printf(
+			"   %s in org %s / space %s\n",
+			terminal.EntityNameColor(app.Name()),
+			terminal.EntityNameColor(app.Organization()),
+			terminal.EntityNameColor(app.Space()),
+		)
+	}
+}
+
+func (c *MigrateAppsCommand) UserWarning(app ApplicationPrinter) {
+	c.say(
+		app,
 		"WARNING: No authorization to migrate app %s to %s in space %s / org %s as %s\n",
 		terminal.EntityNameColor(app.Name()),
 		terminal.EntityNameColor(c.Runtime.String()),
@@ -85,7 +330,8 @@ func (c *MigrateAppsCommand) UserWarning(app ApplicationPrinter) {
 }
 
 func (c *MigrateAppsCommand) FailMigrate(app ApplicationPrinter, err error) {
-	fmt.Printf(
+	c.say(
+		app,
 		"Error: Failed to migrate app %s to %s in space %s / org %s as %s: %s",
 		terminal.EntityNameColor(app.Name()),
 		terminal.EntityNameColor(c.Runtime.String()),
@@ -95,3 +341,204 @@ func (c *MigrateAppsCommand) FailMigrate(app ApplicationPrinter, err error) {
 		terminal.EntityNameColor(err.Error()),
 	)
 }
+
+func (c *MigrateAppsCommand) FailStaging(app ApplicationPrinter, err error) {
+	c.say(
+		app,
+		"\nError: App %s in space %s / org %s failed to stage on %s: %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(c.Runtime.String()),
+		terminal.EntityNameColor(err.Error()),
+	)
+}
+
+func (c *MigrateAppsCommand) FailStart(app ApplicationPrinter) {
+	c.say(
+		app,
+		"\nError: App %s in space %s / org %s is crashing on %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(c.Runtime.String()),
+	)
+}
+
+func (c *MigrateAppsCommand) TimedOutEach(app ApplicationPrinter) {
+	c.say(
+		app,
+		"\nError: Timed out waiting for app %s in space %s / org %s to start on %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(c.Runtime.String()),
+	)
+}
+
+func (c *MigrateAppsCommand) DegradedEach(app ApplicationPrinter, running int, expected int) {
+	c.say(
+		app,
+		"\nError: App %s in space %s / org %s is running %d of the %d instances it ran before on %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		running,
+		expected,
+		terminal.EntityNameColor(c.Runtime.String()),
+	)
+}
+
+func (c *MigrateAppsCommand) FailVerify(app ApplicationPrinter, err error) {
+	c.say(
+		app,
+		"\nError: Failed to verify app %s in space %s / org %s on %s: %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(c.Runtime.String()),
+		terminal.EntityNameColor(err.Error()),
+	)
+}
+
+func (c *MigrateAppsCommand) BeforeRollback(app ApplicationPrinter) {
+	c.PhaseEach(app, "rolling back")
+	c.say(
+		app,
+		"Rolling back app %s in org %s / space %s to %s as %s...\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(c.Runtime.Flip().String()),
+		terminal.EntityNameColor(c.Username),
+	)
+}
+
+func (c *MigrateAppsCommand) CompletedRollback(app ApplicationPrinter) {
+	c.say(
+		app,
+		"Completed rolling back app %s in org %s / space %s to %s as %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(c.Runtime.Flip().String()),
+		terminal.EntityNameColor(c.Username),
+	)
+}
+
+func (c *MigrateAppsCommand) FailRollback(app ApplicationPrinter, err error) {
+	c.say(
+		app,
+		"Error: Failed to roll back app %s to %s in space %s / org %s as %s: %s\n",
+		terminal.EntityNameColor(app.Name()),
+		terminal.EntityNameColor(c.Runtime.Flip().String()),
+		terminal.EntityNameColor(app.Space()),
+		terminal.EntityNameColor(app.Organization()),
+		terminal.EntityNameColor(c.Username),
+		terminal.EntityNameColor(err.Error()),
+	)
+}
+
+func (c *MigrateAppsCommand) JournalWarning(err error) {
+	c.say(
+		nil,
+		"WARNING: Failed to update the migration journal: %s\n",
+		terminal.EntityNameColor(err.Error()),
+	)
+}
+
+func (c *MigrateAppsCommand) BeforeDryRun() {
+	switch {
+	case c.Snapshot != "":
+		fmt.Printf(
+			"Planning the restore of the runtimes recorded in snapshot %s as %s...\n",
+			terminal.EntityNameColor(c.Snapshot),
+			terminal.EntityNameColor(c.Username),
+		)
+	case c.Organization != "" && c.Space != "":
+		fmt.Printf(
+			"Planning migration of apps to %s in org %s / %s as %s...\n",
+			terminal.EntityNameColor(c.Runtime.String()),
+			terminal.EntityNameColor(c.Organization),
+			terminal.EntityNameColor(c.Space),
+			terminal.EntityNameColor(c.Username),
+		)
+	case c.Organization != "":
+		fmt.Printf(
+			"Planning migration of apps to %s in org %s as %s...\n",
+			terminal.EntityNameColor(c.Runtime.String()),
+			terminal.EntityNameColor(c.Organization),
+			terminal.EntityNameColor(c.Username),
+		)
+	default:
+		fmt.Printf(
+			"Planning migration of apps to %s as %s...\n",
+			terminal.EntityNameColor(c.Runtime.String()),
+			terminal.EntityNameColor(c.Username),
+		)
+	}
+}
+
+type appsBySpace []ApplicationPrinter
+
+func (a appsBySpace) Len() int      { return len(a) }
+func (a appsBySpace) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a appsBySpace) Less(i, j int) bool {
+	if a[i].Organization() != a[j].Organization() {
+		return a[i].Organization() < a[j].Organization()
+	}
+	if a[i].Space() != a[j].Space() {
+		return a[i].Space() < a[j].Space()
+	}
+	return a[i].Name() < a[j].Name()
+}
+
+func (c *MigrateAppsCommand) DryRun(apps []ApplicationPrinter) {
+	SayOK()
+
+	sorted := make(appsBySpace, len(apps))
+	copy(sorted, apps)
+	sort.Stable(sorted)
+
+	var (
+		started   int
+		instances int
+		table     terminal.Table
+	)
+
+	for i, app := range sorted {
+		if i == 0 || app.Organization() != sorted[i-1].Organization() || app.Space() != sorted[i-1].Space() {
+			if table != nil {
+				table.Print()
+				fmt.Println()
+			}
+
+			fmt.Printf(
+				"org %s / space %s:\n",
+				terminal.EntityNameColor(app.Organization()),
+				terminal.EntityNameColor(app.Space()),
+			)
+			table = terminal.NewTable(c.UI, []string{"name", "state", "instances"})
+		}
+
+		if app.State() == "started" {
+			started++
+			instances += app.DesiredInstances()
+		}
+
+		table.Add(app.Name(), app.State(), strconv.Itoa(app.DesiredInstances()))
+	}
+
+	if table != nil {
+		table.Print()
+		fmt.Println()
+	}
+
+	fmt.Printf(
+		"Dry run: %d apps would be migrated to %s, %d of them would be restarted (%d instances)\n",
+		len(apps),
+		terminal.EntityNameColor(c.Runtime.String()),
+		started,
+		instances,
+	)
+}
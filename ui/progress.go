@@ -0,0 +1,336 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	sshterminal "golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	progressRefresh  = time.Second
+	progressBarWidth = 30
+)
+
+// Progress shows the apps being migrated in parallel. On a terminal, every app
+// in flight gets a line that is redrawn as it progresses; otherwise every line
+// of output is prefixed with the app it is about.
+type Progress interface {
+	Start(app ApplicationPrinter)
+	Phase(app ApplicationPrinter, phase string)
+	Instances(app ApplicationPrinter, running int, desired int)
+	Finish(app ApplicationPrinter, failed bool)
+	// Say prints a message about the app, or about the migration as a whole
+	// when app is nil.
+	Say(app ApplicationPrinter, message string)
+	// Pause hides the progress until the next app starts, e.g. to prompt.
+	Pause()
+	Stop()
+}
+
+func NewProgress(total int) Progress {
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		return NewLiveProgress(os.Stdout, total, terminalWidth)
+	}
+
+	return LineProgress{Out: os.Stdout}
+}
+
+func terminalWidth() int {
+	width, _, err := sshterminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return 80
+	}
+	return width
+}
+
+func appLabel(app ApplicationPrinter) string {
+	return fmt.Sprintf("%s / %s / %s", app.Organization(), app.Space(), app.Name())
+}
+
+// LineProgress prints each message on its own lines, prefixed with the app,
+// to Out or, without it, to stdout.
+type LineProgress struct {
+	Out io.Writer
+}
+
+func (p LineProgress) Start(app ApplicationPrinter) {}
+
+func (p LineProgress) Phase(app ApplicationPrinter, phase string) {
+	p.Say(app, phase)
+}
+
+func (p LineProgress) Instances(app ApplicationPrinter, running int, desired int) {}
+
+func (p LineProgress) Finish(app ApplicationPrinter, failed bool) {}
+
+func (p LineProgress) Say(app ApplicationPrinter, message string) {
+	out := p.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	for _, line := range messageLines(message) {
+		if app == nil {
+			fmt.Fprintln(out, line)
+			continue
+		}
+		fmt.Fprintf(out, "[%s] %s\n", appLabel(app), line)
+	}
+}
+
+func (p LineProgress) Pause() {}
+
+func (p LineProgress) Stop() {}
+
+func messageLines(message string) []string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+type appProgress struct {
+	app     ApplicationPrinter
+	phase   string
+	started time.Time
+	running int
+	desired int
+}
+
+// LiveProgress redraws a line for each app in flight and an overall progress
+// bar below the messages printed so far.
+type LiveProgress struct {
+	out   io.Writer
+	width func() int
+
+	mutex    sync.Mutex
+	total    int
+	finished int
+	failed   int
+	started  time.Time
+	inFlight []*appProgress
+	drawn    int
+	paused   bool
+	stopped  bool
+
+	done chan struct{}
+	wait sync.WaitGroup
+}
+
+func NewLiveProgress(out io.Writer, total int, width func() int) *LiveProgress {
+	p := &LiveProgress{
+		out:     out,
+		width:   width,
+		total:   total,
+		started: time.Now(),
+		done:    make(chan struct{}),
+	}
+
+	p.wait.Add(1)
+	go p.refresh()
+
+	return p
+}
+
+// refresh redraws periodically so that elapsed times and the ETA keep moving
+// while no app changes state.
+func (p *LiveProgress) refresh() {
+	defer p.wait.Done()
+
+	ticker := time.NewTicker(progressRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.mutex.Lock()
+			p.redraw()
+			p.mutex.Unlock()
+		}
+	}
+}
+
+func (p *LiveProgress) Start(app ApplicationPrinter) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.paused = false
+	p.inFlight = append(p.inFlight, &appProgress{
+		app:     app,
+		phase:   "starting migration",
+		started: time.Now(),
+		desired: app.DesiredInstances(),
+	})
+	p.redraw()
+}
+
+func (p *LiveProgress) Phase(app ApplicationPrinter, phase string) {
+	p.update(app, func(a *appProgress) {
+		a.phase = phase
+	})
+}
+
+func (p *LiveProgress) Instances(app ApplicationPrinter, running int, desired int) {
+	p.update(app, func(a *appProgress) {
+		a.running = running
+		a.desired = desired
+	})
+}
+
+func (p *LiveProgress) update(app ApplicationPrinter, change func(*appProgress)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if a := p.find(app); a != nil {
+		change(a)
+		p.redraw()
+	}
+}
+
+func (p *LiveProgress) Finish(app ApplicationPrinter, failed bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	label := appLabel(app)
+	for i, a := range p.inFlight {
+		if appLabel(a.app) == label {
+			p.inFlight = append(p.inFlight[:i], p.inFlight[i+1:]...)
+			break
+		}
+	}
+
+	p.finished++
+	if failed {
+		p.failed++
+	}
+	p.redraw()
+}
+
+func (p *LiveProgress) Say(app ApplicationPrinter, message string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.clear()
+	for _, line := range messageLines(message) {
+		fmt.Fprintln(p.out, line)
+	}
+	p.draw()
+}
+
+func (p *LiveProgress) Pause() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.clear()
+	p.paused = true
+}
+
+// Stop leaves the final state of the progress bar on the screen. Messages said
+// afterwards are printed as is.
+func (p *LiveProgress) Stop() {
+	close(p.done)
+	p.wait.Wait()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.paused = false
+	p.redraw()
+	p.drawn = 0
+	p.stopped = true
+}
+
+func (p *LiveProgress) find(app ApplicationPrinter) *appProgress {
+	label := appLabel(app)
+	for _, a := range p.inFlight {
+		if appLabel(a.app) == label {
+			return a
+		}
+	}
+	return nil
+}
+
+func (p *LiveProgress) redraw() {
+	p.clear()
+	p.draw()
+}
+
+// clear moves the cursor back to the first line drawn and erases everything
+// below it.
+func (p *LiveProgress) clear() {
+	if p.drawn > 0 {
+		fmt.Fprintf(p.out, "\033[%dA\033[J", p.drawn)
+	}
+	p.drawn = 0
+}
+
+func (p *LiveProgress) draw() {
+	if p.paused || p.stopped {
+		return
+	}
+
+	width := p.width()
+
+	for _, a := range p.inFlight {
+		line := fmt.Sprintf(
+			"   %s  %s  %s  %d/%d instances running",
+			appLabel(a.app),
+			a.phase,
+			formatElapsed(time.Since(a.started)),
+			a.running,
+			a.desired,
+		)
+		fmt.Fprintln(p.out, truncate(line, width))
+		p.drawn++
+	}
+
+	fmt.Fprintln(p.out, truncate(p.bar(), width))
+	p.drawn++
+}
+
+func (p *LiveProgress) bar() string {
+	filled := progressBarWidth
+	if p.total > 0 {
+		filled = progressBarWidth * p.finished / p.total
+	}
+
+	eta := "unknown"
+	if p.finished > 0 {
+		elapsed := time.Since(p.started)
+		eta = formatElapsed(elapsed / time.Duration(p.finished) * time.Duration(p.total-p.finished))
+	}
+
+	return fmt.Sprintf(
+		"[%s%s] %d/%d apps, %d failed, %d in flight, ETA %s",
+		strings.Repeat("#", filled),
+		strings.Repeat("-", progressBarWidth-filled),
+		p.finished,
+		p.total,
+		p.failed,
+		len(p.inFlight),
+		eta,
+	)
+}
+
+func formatElapsed(d time.Duration) string {
+	return d.Truncate(time.Second).String()
+}
+
+// truncate keeps lines from wrapping, which would throw off clear.
+func truncate(line string, width int) string {
+	if len(line) < width {
+		return line
+	}
+	return line[:width-1]
+}
@@ -0,0 +1,178 @@
+package ui_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/diego-enabler/commands/displayhelpers"
+	"github.com/cloudfoundry-incubator/diego-enabler/models"
+	. "github.com/cloudfoundry-incubator/diego-enabler/ui"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Progress", func() {
+	var (
+		buf  *gbytes.Buffer
+		blue ApplicationPrinter
+		red  ApplicationPrinter
+	)
+
+	newApp := func(name string) ApplicationPrinter {
+		return &displayhelpers.AppPrinter{
+			App: models.Application{
+				ApplicationEntity: models.ApplicationEntity{
+					Name:      name,
+					SpaceGuid: "space-guid",
+					Instances: 2,
+				},
+			},
+			Spaces: map[string]models.Space{
+				"space-guid": {
+					SpaceEntity: models.SpaceEntity{
+						Name: "space",
+						Organization: models.Organization{
+							OrganizationEntity: models.OrganizationEntity{Name: "org"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		buf = gbytes.NewBuffer()
+		blue = newApp("blue")
+		red = newApp("red")
+	})
+
+	Describe("LiveProgress", func() {
+		var (
+			progress *LiveProgress
+			width    int
+		)
+
+		BeforeEach(func() {
+			width = 200
+		})
+
+		JustBeforeEach(func() {
+			progress = NewLiveProgress(buf, 4, func() int { return width })
+		})
+
+		It("draws a line for each app in flight and the progress bar", func() {
+			progress.Start(blue)
+			progress.Start(red)
+			progress.Instances(red, 1, 2)
+			progress.Stop()
+
+			Expect(buf).To(gbytes.Say(`org / space / blue  starting migration  0s  0/2 instances running`))
+			Expect(buf).To(gbytes.Say(`org / space / red  starting migration  0s  1/2 instances running`))
+			Expect(buf).To(gbytes.Say(`\[-{30}\] 0/4 apps, 0 failed, 2 in flight, ETA unknown`))
+		})
+
+		It("clears the lines it drew before redrawing them", func() {
+			progress.Start(blue)
+			Expect(string(buf.Contents())).NotTo(ContainSubstring("\033["))
+
+			progress.Start(red)
+			Expect(string(buf.Contents())).To(ContainSubstring("\033[2A\033[J"))
+
+			progress.Phase(red, "staging")
+			progress.Stop()
+
+			Expect(string(buf.Contents())).To(ContainSubstring("\033[3A\033[J"))
+			Expect(string(buf.Contents())).NotTo(ContainSubstring("\033[4A"))
+		})
+
+		It("counts finished and failed apps and estimates the time left", func() {
+			progress.Start(blue)
+			progress.Start(red)
+			progress.Finish(blue, false)
+			progress.Finish(red, true)
+			progress.Stop()
+
+			Expect(buf).To(gbytes.Say(`\[#{15}-{15}\] 2/4 apps, 1 failed, 0 in flight, ETA 0s`))
+		})
+
+		It("prints messages above the progress", func() {
+			progress.Start(blue)
+			progress.Say(blue, "Migrating app blue\n")
+			progress.Stop()
+
+			Expect(buf).To(gbytes.Say("\033\\[2A\033\\[JMigrating app blue\n   org / space / blue"))
+		})
+
+		Context("when the terminal is narrow", func() {
+			BeforeEach(func() {
+				width = 20
+			})
+
+			It("truncates every line to the terminal width", func() {
+				progress.Start(blue)
+				progress.Stop()
+
+				for _, line := range strings.Split(string(buf.Contents()), "\n") {
+					line = strings.TrimPrefix(line, "\033[2A\033[J")
+					Expect(len(line)).To(BeNumerically("<", width))
+				}
+				Expect(buf).To(gbytes.Say(`   org / space / bl\n`))
+			})
+		})
+
+		Context("when paused", func() {
+			It("clears the progress and does not redraw it until the next app starts", func() {
+				progress.Start(blue)
+				progress.Pause()
+				Expect(string(buf.Contents())).To(HaveSuffix("\033[2A\033[J"))
+
+				drawn := len(buf.Contents())
+				progress.Phase(blue, "staging")
+				Consistently(func() int { return len(buf.Contents()) }, "1.5s").Should(Equal(drawn))
+
+				progress.Start(red)
+				Expect(len(buf.Contents())).To(BeNumerically(">", drawn))
+				progress.Stop()
+			})
+		})
+
+		Context("when stopped", func() {
+			It("leaves the last progress on the screen and does not redraw it", func() {
+				progress.Start(blue)
+				progress.Stop()
+				drawn := len(buf.Contents())
+
+				progress.Phase(blue, "staging")
+				progress.Finish(blue, false)
+				Consistently(func() int { return len(buf.Contents()) }, "1.5s").Should(Equal(drawn))
+
+				progress.Say(nil, "Migration completed\n")
+				Expect(string(buf.Contents()[drawn:])).To(Equal("Migration completed\n"))
+			})
+		})
+	})
+
+	Describe("LineProgress", func() {
+		var progress LineProgress
+
+		BeforeEach(func() {
+			progress = LineProgress{Out: buf}
+		})
+
+		It("prefixes every line about an app with the app", func() {
+			progress.Say(blue, "Migrating app blue\n\nstill migrating\n")
+			progress.Phase(red, "staging")
+
+			Expect(buf).To(gbytes.Say(`\[org / space / blue\] Migrating app blue\n`))
+			Expect(buf).To(gbytes.Say(`\[org / space / blue\] still migrating\n`))
+			Expect(buf).To(gbytes.Say(`\[org / space / red\] staging\n`))
+		})
+
+		It("prints messages about the migration as a whole as they are", func() {
+			progress.Say(nil, "Migration completed\n")
+
+			Expect(string(buf.Contents())).To(Equal("Migration completed\n"))
+		})
+	})
+})
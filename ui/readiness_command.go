@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+type ReadinessCommand struct {
+	Username     string
+	Organization string
+	Space        string
+	AppName      string
+	UI           terminal.UI
+}
+
+type ReadinessFinding struct {
+	App         ApplicationPrinter
+	Severity    string
+	Description string
+	Remediation string
+}
+
+func (c *ReadinessCommand) BeforeAll() {
+	switch {
+	case c.AppName != "":
+		fmt.Printf(
+			"Checking whether app %s is ready for the %s runtime as %s...\n",
+			terminal.EntityNameColor(c.AppName),
+			terminal.EntityNameColor(Diego.String()),
+			terminal.EntityNameColor(c.Username),
+		)
+	case c.Space != "" && c.Organization != "":
+		fmt.Printf(
+			"Checking whether apps on the %s runtime in org %s / %s are ready for the %s runtime as %s...\n",
+			terminal.EntityNameColor(DEA.String()),
+			terminal.EntityNameColor(c.Organization),
+			terminal.EntityNameColor(c.Space),
+			terminal.EntityNameColor(Diego.String()),
+			terminal.EntityNameColor(c.Username),
+		)
+	case c.Organization != "":
+		fmt.Printf(
+			"Checking whether apps on the %s runtime in org %s are ready for the %s runtime as %s...\n",
+			terminal.EntityNameColor(DEA.String()),
+			terminal.EntityNameColor(c.Organization),
+			terminal.EntityNameColor(Diego.String()),
+			terminal.EntityNameColor(c.Username),
+		)
+	default:
+		fmt.Printf(
+			"Checking whether apps on the %s runtime are ready for the %s runtime as %s...\n",
+			terminal.EntityNameColor(DEA.String()),
+			terminal.EntityNameColor(Diego.String()),
+			terminal.EntityNameColor(c.Username),
+		)
+	}
+}
+
+func (c *ReadinessCommand) AfterAll(findings []ReadinessFinding, appCount int) {
+	SayOK()
+
+	if len(findings) == 0 {
+		fmt.Printf("No known incompatibilities found in %d apps\n", appCount)
+		return
+	}
+
+	headers := []string{
+		"name",
+		"space",
+		"org",
+		"severity",
+		"finding",
+		"remediation",
+	}
+	t := terminal.NewTable(c.UI, headers)
+
+	var errors, warnings int
+	for _, finding := range findings {
+		severity := finding.Severity
+		if severity == "error" {
+			errors++
+			severity = terminal.FailureColor(severity)
+		} else {
+			warnings++
+			severity = terminal.WarningColor(severity)
+		}
+
+		t.Add(
+			finding.App.Name(),
+			finding.App.Space(),
+			finding.App.Organization(),
+			severity,
+			finding.Description,
+			finding.Remediation,
+		)
+	}
+
+	t.Print()
+
+	fmt.Printf("\n%d errors and %d warnings found in %d apps\n", errors, warnings, appCount)
+}
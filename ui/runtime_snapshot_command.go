@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+type RuntimeSnapshotCommand struct {
+	Username string
+	UI       terminal.UI
+}
+
+// SnapshotApp is an app as it appears in a runtime snapshot.
+type SnapshotApp struct {
+	Organization string
+	Space        string
+	Name         string
+	Runtime      Runtime
+}
+
+// SnapshotDiff lists the apps that changed between two runtime snapshots.
+type SnapshotDiff struct {
+	ToDiego     []SnapshotApp
+	ToDEA       []SnapshotApp
+	Appeared    []SnapshotApp
+	Disappeared []SnapshotApp
+}
+
+func (d SnapshotDiff) Empty() bool {
+	return len(d.ToDiego) == 0 && len(d.ToDEA) == 0 && len(d.Appeared) == 0 && len(d.Disappeared) == 0
+}
+
+func (c *RuntimeSnapshotCommand) BeforeSave(path string) {
+	fmt.Printf(
+		"Saving a runtime snapshot to %s as %s...\n",
+		terminal.EntityNameColor(path),
+		terminal.EntityNameColor(c.Username),
+	)
+}
+
+func (c *RuntimeSnapshotCommand) AfterSave(appCount int) {
+	SayOK()
+	fmt.Printf("Saved the runtime of %d apps\n", appCount)
+}
+
+// BeforeDiff announces the comparison; an empty newPath stands for the apps
+// as they are running now.
+func (c *RuntimeSnapshotCommand) BeforeDiff(oldPath string, newPath string) {
+	if newPath == "" {
+		fmt.Printf(
+			"Comparing runtime snapshot %s to the running apps as %s...\n",
+			terminal.EntityNameColor(oldPath),
+			terminal.EntityNameColor(c.Username),
+		)
+		return
+	}
+
+	fmt.Printf(
+		"Comparing runtime snapshot %s to %s as %s...\n",
+		terminal.EntityNameColor(oldPath),
+		terminal.EntityNameColor(newPath),
+		terminal.EntityNameColor(c.Username),
+	)
+}
+
+func (c *RuntimeSnapshotCommand) AfterDiff(diff SnapshotDiff) {
+	SayOK()
+
+	if diff.Empty() {
+		fmt.Println("No apps changed runtime, appeared or disappeared")
+		return
+	}
+
+	t := terminal.NewTable(c.UI, []string{"change", "org", "space", "app", "runtime"})
+	addRows := func(change string, apps []SnapshotApp) {
+		for _, app := range apps {
+			t.Add(change, app.Organization, app.Space, app.Name, app.Runtime.String())
+		}
+	}
+	addRows(fmt.Sprintf("moved to %s", Diego), diff.ToDiego)
+	addRows(fmt.Sprintf("moved back to %s", DEA), diff.ToDEA)
+	addRows("appeared", diff.Appeared)
+	addRows("disappeared", diff.Disappeared)
+	t.Print()
+
+	fmt.Printf(
+		"\n%d moved to %s, %d moved back to %s, %d appeared, %d disappeared\n",
+		len(diff.ToDiego),
+		Diego,
+		len(diff.ToDEA),
+		DEA,
+		len(diff.Appeared),
+		len(diff.Disappeared),
+	)
+}
+
+// MissingApps lists the apps of a restored snapshot that no longer exist, and
+// so were left alone.
+func (c *RuntimeSnapshotCommand) MissingApps(apps []SnapshotApp) {
+	fmt.Printf("\nApps in the snapshot that no longer exist:\n")
+	for _, app := range apps {
+		fmt.Printf(
+			"   %s in org %s / space %s\n",
+			terminal.EntityNameColor(app.Name),
+			terminal.EntityNameColor(app.Organization),
+			terminal.EntityNameColor(app.Space),
+		)
+	}
+}
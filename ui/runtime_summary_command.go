@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/cli/cf/terminal"
+)
+
+type RuntimeSummaryCommand struct {
+	Username     string
+	Organization string
+	UI           terminal.UI
+}
+
+// RuntimeUsage counts apps, and the memory of every instance of the started
+// apps, on each runtime.
+type RuntimeUsage struct {
+	DEAApps     int
+	DiegoApps   int
+	DEAMemory   int64 // in Megabytes
+	DiegoMemory int64 // in Megabytes
+}
+
+func (u RuntimeUsage) Apps() int {
+	return u.DEAApps + u.DiegoApps
+}
+
+func (u RuntimeUsage) Memory() int64 {
+	return u.DEAMemory + u.DiegoMemory
+}
+
+// AllDEA reports whether none of the apps run on Diego yet.
+func (u RuntimeUsage) AllDEA() bool {
+	return u.Apps() > 0 && u.DiegoApps == 0
+}
+
+type OrgRuntimeUsage struct {
+	Organization string
+	RuntimeUsage
+}
+
+type SpaceRuntimeUsage struct {
+	Organization string
+	Space        string
+	RuntimeUsage
+}
+
+type RuntimeSummary struct {
+	Orgs   []OrgRuntimeUsage
+	Spaces []SpaceRuntimeUsage
+	Total  RuntimeUsage
+}
+
+func (c *RuntimeSummaryCommand) BeforeAll() {
+	if c.Organization != "" {
+		fmt.Printf(
+			"Getting the runtime summary of org %s as %s...\n",
+			terminal.EntityNameColor(c.Organization),
+			terminal.EntityNameColor(c.Username),
+		)
+		return
+	}
+
+	fmt.Printf(
+		"Getting the runtime summary as %s...\n",
+		terminal.EntityNameColor(c.Username),
+	)
+}
+
+func (c *RuntimeSummaryCommand) AfterAll(summary RuntimeSummary) {
+	SayOK()
+
+	if summary.Total.Apps() == 0 {
+		fmt.Println("No apps found")
+		return
+	}
+
+	usageHeaders := []string{"apps", "on DEA", "on Diego", "memory", "on DEA", "on Diego"}
+
+	t := terminal.NewTable(c.UI, append([]string{"org"}, usageHeaders...))
+	for _, org := range summary.Orgs {
+		t.Add(append([]string{org.Organization}, usageCells(org.RuntimeUsage)...)...)
+	}
+	t.Add(append([]string{terminal.HeaderColor("total")}, usageCells(summary.Total)...)...)
+	t.Print()
+
+	fmt.Println()
+
+	t = terminal.NewTable(c.UI, append([]string{"org", "space"}, usageHeaders...))
+	for _, space := range summary.Spaces {
+		t.Add(append([]string{space.Organization, space.Space}, usageCells(space.RuntimeUsage)...)...)
+	}
+	t.Print()
+
+	fmt.Printf(
+		"\n%s of %d apps and %s of %s memory run on %s\n",
+		percentage(int64(summary.Total.DiegoApps), int64(summary.Total.Apps())),
+		summary.Total.Apps(),
+		percentage(summary.Total.DiegoMemory, summary.Total.Memory()),
+		formatMegabytes(summary.Total.Memory()),
+		terminal.EntityNameColor(Diego.String()),
+	)
+}
+
+func usageCells(usage RuntimeUsage) []string {
+	return []string{
+		fmt.Sprintf("%d", usage.Apps()),
+		fmt.Sprintf("%d (%s)", usage.DEAApps, percentage(int64(usage.DEAApps), int64(usage.Apps()))),
+		fmt.Sprintf("%d (%s)", usage.DiegoApps, percentage(int64(usage.DiegoApps), int64(usage.Apps()))),
+		formatMegabytes(usage.Memory()),
+		fmt.Sprintf("%s (%s)", formatMegabytes(usage.DEAMemory), percentage(usage.DEAMemory, usage.Memory())),
+		fmt.Sprintf("%s (%s)", formatMegabytes(usage.DiegoMemory), percentage(usage.DiegoMemory, usage.Memory())),
+	}
+}
+
+// percentage is rounded down, so that nothing short of all of it shows as
+// 100%.
+func percentage(part int64, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", part*100/total)
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type Runtime string
@@ -45,4 +46,15 @@ type ApplicationPrinter interface {
 	Name() string
 	Organization() string
 	Space() string
+	State() string
+	DesiredInstances() int
+	RunningInstances() int
+	Memory() int64    // in Megabytes
+	DiskQuota() int64 // in Megabytes
+	Stack() string
+	// Buildpack is the app's buildpack, the buildpack detected for it, or its
+	// docker image.
+	Buildpack() string
+	HealthCheckType() string
+	PackageUpdatedAt() *time.Time
 }